@@ -0,0 +1,220 @@
+// Package config provides reading configuration files describing what to lint and how
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	TargetURL  = "url"  // target is a server URL to get the certificate chain and stapled OCSP response from
+	TargetCert = "cert" // target is an ASN.1 DER encoded certificate file to send an OCSP request for
+	TargetResp = "resp" // target is an OCSP response file
+)
+
+// Target defines the struct of a single input to lint
+type Target struct {
+	Target     string `json:"target"`      // server URL or file path, depending on Type
+	Type       string `json:"type"`        // one of TargetURL (default), TargetCert or TargetResp
+	IssuerCert string `json:"issuer_cert"` // issuer certificate file, default fetch from certificate
+	OCSPURL    string `json:"ocsp_url"`    // URL to send OCSP requests to, default fetch from certificate
+	Method     string `json:"method"`      // HTTP method to send OCSP requests with, GET (default) or POST
+}
+
+// Thresholds defines the struct of overrides for linter.Thresholds as duration strings (e.g. "96h")
+type Thresholds struct {
+	ProducedAtLimitSubscriber   string `json:"produced_at_limit_subscriber"`
+	ThisUpdateLimitSubscriber   string `json:"this_update_limit_subscriber"`
+	ProducedAtLimitCA           string `json:"produced_at_limit_ca"`
+	ThisUpdateLimitCA           string `json:"this_update_limit_ca"`
+	NextUpdateLimitSubscriber   string `json:"next_update_limit_subscriber"`
+	ClockSkewTolerance          string `json:"clock_skew_tolerance"`
+	NextUpdateMinimumSubscriber string `json:"next_update_minimum_subscriber"`
+	ResponderCertLifetimeLimit  string `json:"responder_cert_lifetime_limit"`
+}
+
+// Config defines the struct of a configuration file
+type Config struct {
+	Targets        []*Target  `json:"targets"`
+	Profile        string     `json:"profile"`         // name of the lint profile to use
+	IncludeLints   []string   `json:"include_lints"`   // ids of the only lints to run
+	ExcludeLints   []string   `json:"exclude_lints"`   // ids of lints not to run
+	IncludeSources []string   `json:"include_sources"` // only run lints whose source contains one of these
+	Thresholds     Thresholds `json:"thresholds"`      // overrides for the thresholds of the profile
+	RespTimeLimit  string     `json:"resp_time_limit"` // time limit for the OCSP response to be served, e.g. "10s"
+	Timeout        string     `json:"timeout"`         // time limit for HTTP responses before timing out, e.g. "20s"
+	Nonce          bool       `json:"nonce"`           // whether to send a random nonce in OCSP requests
+}
+
+// ReadConfig takes a path to a JSON configuration file and reads, parses and validates it
+func ReadConfig(configFile string) (*Config, error) {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading configuration file: %w", err)
+	}
+
+	return ParseConfig(data)
+}
+
+// ParseConfig parses and validates a JSON configuration
+func ParseConfig(data []byte) (*Config, error) {
+	conf := &Config{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields() // catch misspelt options instead of silently ignoring them
+	err := dec.Decode(conf)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing configuration: %w", err)
+	}
+
+	for idx, target := range conf.Targets {
+		err := target.validate()
+		if err != nil {
+			return nil, fmt.Errorf("Error in target %d: %w", idx, err)
+		}
+	}
+
+	_, err = conf.ApplyThresholds(linter.DefaultThresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conf.RespTimeLimitDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conf.TimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// URI returns a URI identifying the target, an https URI for server URLs and a file URI for files
+func (t *Target) URI() string {
+	if t.Type == TargetURL || t.Type == "" {
+		return (&url.URL{Scheme: "https", Host: t.Target}).String()
+	}
+
+	path, err := filepath.Abs(t.Target)
+	if err != nil {
+		path = t.Target
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// validate checks that a target is well formed and fills in defaults
+func (t *Target) validate() error {
+	if t.Target == "" {
+		return fmt.Errorf("No target specified")
+	}
+
+	switch t.Type {
+	case "":
+		t.Type = TargetURL
+	case TargetURL, TargetCert, TargetResp:
+	default:
+		return fmt.Errorf("Unknown target type %s, must be one of %s, %s or %s", t.Type, TargetURL, TargetCert, TargetResp)
+	}
+
+	switch strings.ToUpper(t.Method) {
+	case "":
+		t.Method = http.MethodGet
+	case http.MethodGet, http.MethodPost:
+		t.Method = strings.ToUpper(t.Method)
+	default:
+		return fmt.Errorf("Unknown HTTP method %s, must be GET or POST", t.Method)
+	}
+
+	return nil
+}
+
+// ApplyThresholds returns thresholds with the overrides of the configuration applied
+func (c *Config) ApplyThresholds(thresholds linter.Thresholds) (linter.Thresholds, error) {
+	overrides := []struct {
+		name      string
+		value     string
+		threshold *time.Duration
+	}{
+		{"produced_at_limit_subscriber", c.Thresholds.ProducedAtLimitSubscriber, &thresholds.ProducedAtLimitSubscriber},
+		{"this_update_limit_subscriber", c.Thresholds.ThisUpdateLimitSubscriber, &thresholds.ThisUpdateLimitSubscriber},
+		{"produced_at_limit_ca", c.Thresholds.ProducedAtLimitCA, &thresholds.ProducedAtLimitCA},
+		{"this_update_limit_ca", c.Thresholds.ThisUpdateLimitCA, &thresholds.ThisUpdateLimitCA},
+		{"next_update_limit_subscriber", c.Thresholds.NextUpdateLimitSubscriber, &thresholds.NextUpdateLimitSubscriber},
+		{"clock_skew_tolerance", c.Thresholds.ClockSkewTolerance, &thresholds.ClockSkewTolerance},
+		{"next_update_minimum_subscriber", c.Thresholds.NextUpdateMinimumSubscriber, &thresholds.NextUpdateMinimumSubscriber},
+		{"responder_cert_lifetime_limit", c.Thresholds.ResponderCertLifetimeLimit, &thresholds.ResponderCertLifetimeLimit},
+	}
+
+	for _, override := range overrides {
+		if override.value == "" {
+			continue
+		}
+
+		d, err := parseDuration(override.name, override.value)
+		if err != nil {
+			return thresholds, err
+		}
+		*override.threshold = d
+	}
+
+	return thresholds, nil
+}
+
+// RespTimeLimitDuration returns the configured time limit for OCSP responses to be served, or 0 if not set
+func (c *Config) RespTimeLimitDuration() (time.Duration, error) {
+	if c.RespTimeLimit == "" {
+		return 0, nil
+	}
+
+	return parsePositiveDuration("resp_time_limit", c.RespTimeLimit)
+}
+
+// TimeoutDuration returns the configured time limit for HTTP responses, or 0 if not set
+func (c *Config) TimeoutDuration() (time.Duration, error) {
+	if c.Timeout == "" {
+		return 0, nil
+	}
+
+	return parsePositiveDuration("timeout", c.Timeout)
+}
+
+// parseDuration parses the value of the named option as a duration that is not negative
+// A zero duration is allowed, e.g. a clock skew tolerance of 0 does not tolerate any clock skew
+func parseDuration(name string, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse %s %s as a duration: %w", name, value, err)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("%s %s must not be a negative duration", name, value)
+	}
+
+	return d, nil
+}
+
+// parsePositiveDuration parses the value of the named option as a positive duration,
+// for options where a zero duration would mean the option is not set
+func parsePositiveDuration(name string, value string) (time.Duration, error) {
+	d, err := parseDuration(name, value)
+	if err != nil {
+		return 0, err
+	}
+
+	if d == 0 {
+		return 0, fmt.Errorf("%s %s must be a positive duration", name, value)
+	}
+
+	return d, nil
+}
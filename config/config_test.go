@@ -0,0 +1,135 @@
+package config
+
+import (
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	GoodConfig = "../testdata/configs/config.json" // sample configuration file
+	BadPath    = "blah///blah/blah.blah"           // bad file path
+)
+
+// TestReadConfig tests ReadConfig, which reads, parses and validates a configuration file
+func TestReadConfig(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		conf, err := ReadConfig(GoodConfig)
+		if err != nil {
+			t.Fatalf("Got error reading good configuration file: %s", err.Error())
+		}
+
+		if conf.Profile != "cabf" || len(conf.ExcludeLints) != 1 || len(conf.Targets) != 3 || !conf.Nonce {
+			t.Errorf("Configuration file was parsed incorrectly: %+v", conf)
+		}
+
+		if conf.Targets[0].Type != TargetURL || conf.Targets[0].Method != http.MethodPost {
+			t.Errorf("Target should be a server URL using POST, instead is a %s using %s", conf.Targets[0].Type, conf.Targets[0].Method)
+		}
+
+		if conf.Targets[1].Type != TargetCert || conf.Targets[1].Method != http.MethodGet {
+			t.Errorf("Target should be a certificate file using GET, instead is a %s using %s", conf.Targets[1].Type, conf.Targets[1].Method)
+		}
+	})
+
+	t.Run("Bad file path", func(t *testing.T) {
+		_, err := ReadConfig(BadPath)
+		if err == nil {
+			t.Errorf("Should have gotten error reading bad file path")
+		}
+	})
+}
+
+// TestParseConfig tests ParseConfig, which parses and validates a configuration
+func TestParseConfig(t *testing.T) {
+	badConfigs := map[string]string{
+		"Not JSON":             `blah`,
+		"Unknown option":       `{"blah": true}`,
+		"Target without name":  `{"targets": [{"type": "cert"}]}`,
+		"Unknown target type":  `{"targets": [{"target": "a", "type": "blah"}]}`,
+		"Unknown HTTP method":  `{"targets": [{"target": "a", "method": "PUT"}]}`,
+		"Bad threshold":        `{"thresholds": {"produced_at_limit_ca": "blah"}}`,
+		"Negative threshold":   `{"thresholds": {"produced_at_limit_ca": "-1h"}}`,
+		"Bad response limit":   `{"resp_time_limit": "blah"}`,
+		"Bad timeout duration": `{"timeout": "0s"}`,
+		"Zero response limit":  `{"resp_time_limit": "0s"}`,
+	}
+
+	for name, data := range badConfigs {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(data))
+			if err == nil {
+				t.Errorf("Should have gotten error parsing bad configuration %s", data)
+			}
+		})
+	}
+}
+
+// TestTargetURI tests Target.URI, which returns a URI identifying a target
+func TestTargetURI(t *testing.T) {
+	t.Run("Server URL", func(t *testing.T) {
+		target := &Target{Target: "google.com:443", Type: TargetURL}
+		if uri := target.URI(); uri != "https://google.com:443" {
+			t.Errorf("Server URL should have an https URI, instead got %s", uri)
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		target := &Target{Target: "../testdata/resps/oldfbresp", Type: TargetResp}
+		uri, err := url.Parse(target.URI())
+		if err != nil {
+			t.Fatalf("File should have a valid URI, instead got error: %s", err.Error())
+		}
+
+		if uri.Scheme != "file" || !strings.HasSuffix(uri.Path, "/testdata/resps/oldfbresp") {
+			t.Errorf("File should have an absolute file URI, instead got %s", uri)
+		}
+	})
+}
+
+// TestApplyThresholds tests ApplyThresholds, which overrides thresholds with the configured ones
+func TestApplyThresholds(t *testing.T) {
+	conf, err := ReadConfig(GoodConfig)
+	if err != nil {
+		t.Fatalf("Got error reading good configuration file: %s", err.Error())
+	}
+
+	thresholds, err := conf.ApplyThresholds(linter.DefaultThresholds)
+	if err != nil {
+		t.Fatalf("Got error applying thresholds: %s", err.Error())
+	}
+
+	if thresholds.ProducedAtLimitSubscriber != 48*time.Hour || thresholds.NextUpdateLimitSubscriber != 168*time.Hour ||
+		thresholds.ClockSkewTolerance != time.Minute {
+		t.Errorf("Configured thresholds were not applied: %+v", thresholds)
+	}
+
+	if thresholds.ProducedAtLimitCA != linter.DefaultThresholds.ProducedAtLimitCA {
+		t.Errorf("Thresholds that are not configured should not change: %+v", thresholds)
+	}
+
+	respTimeLimit, err := conf.RespTimeLimitDuration()
+	if err != nil || respTimeLimit != 5*time.Second {
+		t.Errorf("Response time limit should be 5s, instead got %s: %v", respTimeLimit, err)
+	}
+
+	timeout, err := conf.TimeoutDuration()
+	if err != nil || timeout != 10*time.Second {
+		t.Errorf("Timeout should be 10s, instead got %s: %v", timeout, err)
+	}
+
+	t.Run("Zero clock skew tolerance", func(t *testing.T) {
+		conf, err := ParseConfig([]byte(`{"thresholds": {"clock_skew_tolerance": "0s"}}`))
+		if err != nil {
+			t.Fatalf("Got error parsing configuration with zero clock skew tolerance: %s", err.Error())
+		}
+
+		thresholds, err := conf.ApplyThresholds(linter.DefaultThresholds)
+		if err != nil || thresholds.ClockSkewTolerance != 0 {
+			t.Errorf("Clock skew tolerance should be 0s, instead got %s: %v", thresholds.ClockSkewTolerance, err)
+		}
+	})
+}
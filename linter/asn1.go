@@ -0,0 +1,188 @@
+package linter
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+)
+
+// OIDPKIXOCSPBasic is the OID of the id-pkix-ocsp-basic response type (RFC 6960 section 4.2.1)
+var OIDPKIXOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+
+// The following structs mirror the ASN.1 structure of an OCSP response (RFC 6960 section 4.2.1).
+// Unlike the structs used by ocsp.ParseResponse, they keep the raw encoding of every part of
+// the response that is needed to check encoding level properties lost by parsing.
+
+// RawOCSPResponse mirrors OCSPResponse
+type RawOCSPResponse struct {
+	Raw           asn1.RawContent
+	Status        asn1.Enumerated
+	ResponseBytes RawResponseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+// RawResponseBytes mirrors ResponseBytes
+type RawResponseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+// RawBasicResponse mirrors BasicOCSPResponse
+type RawBasicResponse struct {
+	Raw                asn1.RawContent
+	TBSResponseData    RawResponseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certs              []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+// RawResponseData mirrors ResponseData
+type RawResponseData struct {
+	Raw         asn1.RawContent
+	Version     asn1.RawValue `asn1:"explicit,tag:0,optional"` // the whole [0] element, empty if not encoded
+	ResponderID asn1.RawValue
+	ProducedAt  asn1.RawValue // kept raw so that non canonical times can still be linted
+	Responses   []RawSingleResponse
+	Extensions  []asn1.RawValue `asn1:"explicit,tag:1,optional"`
+}
+
+// RawSingleResponse mirrors SingleResponse
+type RawSingleResponse struct {
+	Raw        asn1.RawContent
+	CertID     RawCertID
+	CertStatus asn1.RawValue
+	ThisUpdate asn1.RawValue   // kept raw so that non canonical times can still be linted
+	NextUpdate asn1.RawValue   `asn1:"explicit,tag:0,optional"` // the whole [0] element, empty if not encoded
+	Extensions []asn1.RawValue `asn1:"explicit,tag:1,optional"`
+}
+
+// RawCertID mirrors CertID
+type RawCertID struct {
+	Raw            asn1.RawContent
+	HashAlgorithm  pkix.AlgorithmIdentifier
+	IssuerNameHash []byte
+	IssuerKeyHash  []byte
+	SerialNumber   *big.Int
+}
+
+// ASN1Response defines the struct of the ASN.1 structure of a DER encoded OCSP response
+type ASN1Response struct {
+	OCSPResponse  RawOCSPResponse
+	BasicResponse *RawBasicResponse // nil if the response has no response bytes or they are not a basic response
+}
+
+// ParseASN1Response parses a DER encoded OCSP response into its ASN.1 structure
+func ParseASN1Response(der []byte) (*ASN1Response, error) {
+	parsed := &ASN1Response{}
+
+	rest, err := asn1.Unmarshal(der, &parsed.OCSPResponse)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OCSPResponse: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("Trailing data after OCSPResponse")
+	}
+
+	responseBytes := parsed.OCSPResponse.ResponseBytes
+	if !responseBytes.ResponseType.Equal(OIDPKIXOCSPBasic) {
+		return parsed, nil
+	}
+
+	parsed.BasicResponse = &RawBasicResponse{}
+	rest, err = asn1.Unmarshal(responseBytes.Response, parsed.BasicResponse)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing BasicOCSPResponse: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("Trailing data after BasicOCSPResponse")
+	}
+
+	return parsed, nil
+}
+
+// parseResponseStatus parses only the responseStatus of a DER encoded OCSP response,
+// so that it is known even if the rest of the response cannot be parsed
+func parseResponseStatus(der []byte) (ocsp.ResponseStatus, error) {
+	// trailing fields of the SEQUENCE are left unparsed
+	var resp struct {
+		Status asn1.Enumerated
+	}
+	if _, err := asn1.Unmarshal(der, &resp); err != nil {
+		return 0, fmt.Errorf("Error parsing OCSPResponse: %w", err)
+	}
+
+	return ocsp.ResponseStatus(resp.Status), nil
+}
+
+// walkDER calls visit on every element of der, descending into constructed elements
+func walkDER(der []byte, visit func(elem asn1.RawValue)) error {
+	for len(der) > 0 {
+		var elem asn1.RawValue
+		rest, err := asn1.Unmarshal(der, &elem)
+		if err != nil {
+			return err
+		}
+
+		visit(elem)
+
+		if elem.IsCompound {
+			err = walkDER(elem.Bytes, visit)
+			if err != nil {
+				return err
+			}
+		}
+
+		der = rest
+	}
+
+	return nil
+}
+
+// sequenceElements returns the elements of the contents of a DER encoded SEQUENCE
+func sequenceElements(contents []byte) ([]asn1.RawValue, error) {
+	var elems []asn1.RawValue
+	for len(contents) > 0 {
+		var elem asn1.RawValue
+		rest, err := asn1.Unmarshal(contents, &elem)
+		if err != nil {
+			return nil, err
+		}
+
+		elems = append(elems, elem)
+		contents = rest
+	}
+
+	return elems, nil
+}
+
+// singleResponseIndex returns the index of the first SingleResponse of a basic OCSP response
+// whose CertID has the given serial number, or -1 if there is none
+func singleResponseIndex(parsed *ASN1Response, serial *big.Int) int {
+	if parsed.BasicResponse == nil || serial == nil {
+		return -1
+	}
+
+	for idx, single := range parsed.BasicResponse.TBSResponseData.Responses {
+		if single.CertID.SerialNumber != nil && single.CertID.SerialNumber.Cmp(serial) == 0 {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// getSingleResponse returns the SingleResponse of the ASN.1 structure of ctx that Resp was parsed from,
+// or nil if it is not available
+func getSingleResponse(ctx *LintContext) *RawSingleResponse {
+	if ctx.ASN1 == nil || ctx.ASN1.BasicResponse == nil {
+		return nil
+	}
+
+	responses := ctx.ASN1.BasicResponse.TBSResponseData.Responses
+	if ctx.SingleResponse < 0 || ctx.SingleResponse >= len(responses) {
+		return nil
+	}
+
+	return &responses[ctx.SingleResponse]
+}
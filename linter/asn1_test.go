@@ -0,0 +1,137 @@
+package linter
+
+import (
+	"encoding/asn1"
+	"io/ioutil"
+	"testing"
+)
+
+// derElem returns the DER encoding of an element with the given class, tag and contents
+func derElem(class int, tag int, compound bool, contents ...[]byte) []byte {
+	var bytes []byte
+	for _, content := range contents {
+		bytes = append(bytes, content...)
+	}
+
+	der, err := asn1.Marshal(asn1.RawValue{Class: class, Tag: tag, IsCompound: compound, Bytes: bytes})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return der
+}
+
+// derSeq returns the DER encoding of a SEQUENCE of the given elements
+func derSeq(elems ...[]byte) []byte {
+	return derElem(asn1.ClassUniversal, asn1.TagSequence, true, elems...)
+}
+
+// derExplicit returns the DER encoding of an element explicitly tagged with the given context specific tag
+func derExplicit(tag int, elem []byte) []byte {
+	return derElem(asn1.ClassContextSpecific, tag, true, elem)
+}
+
+// derMarshal returns the DER encoding of val, panicking if it can't be encoded
+func derMarshal(val interface{}) []byte {
+	der, err := asn1.Marshal(val)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return der
+}
+
+// testASN1Resp defines the struct of the encoded parts of a basic OCSP response used to test
+// lints on the raw encoding, its zero value is replaced by well formed parts
+type testASN1Resp struct {
+	ResponseType  asn1.ObjectIdentifier
+	Version       []byte // encoded [0] version element, omitted if nil
+	ProducedAt    string
+	SingleExts    [][]byte // encoded single extensions, omitted if nil
+	ResponseExts  [][]byte // encoded response extensions, omitted if nil
+	ExtraTBSField []byte   // element appended to ResponseData, omitted if nil
+}
+
+// der returns the DER encoding of the OCSP response
+func (r testASN1Resp) der() []byte {
+	responseType := r.ResponseType
+	if responseType == nil {
+		responseType = OIDPKIXOCSPBasic
+	}
+
+	producedAt := r.ProducedAt
+	if producedAt == "" {
+		producedAt = "20200101000000Z"
+	}
+
+	sha1 := derSeq(derMarshal(asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}), derMarshal(asn1.NullRawValue))
+	certID := derSeq(sha1, derMarshal(make([]byte, 20)), derMarshal(make([]byte, 20)), derMarshal(1))
+	singleResp := [][]byte{
+		certID,
+		derElem(asn1.ClassContextSpecific, 0, false), // good
+		derElem(asn1.ClassUniversal, TagGeneralizedTime, false, []byte("20200101000000Z")),
+	}
+	if r.SingleExts != nil {
+		singleResp = append(singleResp, derExplicit(1, derSeq(r.SingleExts...)))
+	}
+
+	var tbs [][]byte
+	if r.Version != nil {
+		tbs = append(tbs, r.Version)
+	}
+	tbs = append(tbs,
+		derExplicit(2, derMarshal(make([]byte, 20))), // responder id by key
+		derElem(asn1.ClassUniversal, TagGeneralizedTime, false, []byte(producedAt)),
+		derSeq(derSeq(singleResp...)),
+	)
+	if r.ResponseExts != nil {
+		tbs = append(tbs, derExplicit(1, derSeq(r.ResponseExts...)))
+	}
+	if r.ExtraTBSField != nil {
+		tbs = append(tbs, r.ExtraTBSField)
+	}
+
+	sha256WithRSA := derSeq(derMarshal(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}), derMarshal(asn1.NullRawValue))
+	basicResp := derSeq(derSeq(tbs...), sha256WithRSA, derMarshal(asn1.BitString{Bytes: []byte{1}, BitLength: 8}))
+
+	responseBytes := derSeq(derMarshal(responseType), derMarshal(basicResp))
+	return derSeq(derMarshal(asn1.Enumerated(0)), derExplicit(0, responseBytes))
+}
+
+// TestParseASN1Response tests ParseASN1Response, which parses a DER encoded
+// OCSP response into its ASN.1 structure
+func TestParseASN1Response(t *testing.T) {
+	rawResp, err := ioutil.ReadFile(RespBadDates)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	t.Run("Happy path", func(t *testing.T) {
+		parsed, err := ParseASN1Response(rawResp)
+		if err != nil {
+			t.Fatalf("Got error parsing OCSP Response: %s", err.Error())
+		}
+
+		if parsed.BasicResponse == nil || len(parsed.BasicResponse.TBSResponseData.Responses) != 1 {
+			t.Errorf("Should have parsed a basic OCSP Response with one single response")
+		}
+	})
+
+	t.Run("Trailing data", func(t *testing.T) {
+		_, err := ParseASN1Response(append(rawResp, 0))
+		if err == nil {
+			t.Errorf("Should have gotten error parsing OCSP Response with trailing data")
+		}
+	})
+
+	t.Run("Not basic response type", func(t *testing.T) {
+		parsed, err := ParseASN1Response(testASN1Resp{ResponseType: asn1.ObjectIdentifier{1, 2, 3}}.der())
+		if err != nil {
+			t.Fatalf("Got error parsing OCSP Response: %s", err.Error())
+		}
+
+		if parsed.BasicResponse != nil {
+			t.Errorf("Should not have parsed a response that is not a basic OCSP Response")
+		}
+	})
+}
@@ -6,7 +6,10 @@ import (
 	"crypto/x509"
 	"fmt"
 	"golang.org/x/crypto/ocsp"
-	"sort"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // StatusIntMap maps ocsp statuses to strings
@@ -17,98 +20,720 @@ var StatusIntMap = map[int]string{
 	// ocsp.SeverFailed is never used: godoc.org/golang.org/x/crypto/ocsp#pkg-constants
 }
 
+// ResponseStatusStrings maps OCSP responseStatus values to their names in RFC 6960
+var ResponseStatusStrings = map[ocsp.ResponseStatus]string{
+	ocsp.Success:           "successful",
+	ocsp.Malformed:         "malformedRequest",
+	ocsp.InternalError:     "internalError",
+	ocsp.TryLater:          "tryLater",
+	ocsp.SignatureRequired: "sigRequired",
+	ocsp.Unauthorized:      "unauthorized",
+}
+
+// ResponseStatusString returns the name of an OCSP responseStatus value, or its numeric value
+// if RFC 6960 does not assign it, e.g. "unknown (4)"
+func ResponseStatusString(status ocsp.ResponseStatus) string {
+	if name, ok := ResponseStatusStrings[status]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(status))
+}
+
+// Transport defines how the OCSP response being linted was obtained
+type Transport string
+
+const (
+	TransportFile    Transport = "file"    // OCSP response was read from a file
+	TransportStapled Transport = "stapled" // OCSP response was stapled to a TLS handshake
+	TransportFetched Transport = "fetched" // OCSP response was fetched from an OCSP responder
+)
+
+// HTTPMetadata defines the struct of the http response an OCSP response was received in, without its body
+type HTTPMetadata struct {
+	StatusCode int           // status code of the http response
+	Header     http.Header   // headers of the http response
+	Latency    time.Duration // time taken for the OCSP responder to respond
+}
+
+// LintContext defines the struct of everything a lint is given to check
+type LintContext struct {
+	Resp           *ocsp.Response      // the parsed OCSP response, nil if ResponseStatus is not successful
+	RawResp        []byte              // the DER encoded OCSP response, may be nil
+	ASN1           *ASN1Response       // the ASN.1 structure of RawResp, nil if RawResp is nil or could not be parsed
+	SingleResponse int                 // the index of the SingleResponse of ASN1 that Resp was parsed from
+	ResponseStatus ocsp.ResponseStatus // the responseStatus of the OCSP response
+	LeafCert       *x509.Certificate   // the certificate the OCSP response is for, may be nil
+	IssuerCert     *x509.Certificate   // the certificate of the issuer of LeafCert, may be nil
+	Request        *ocsp.Request       // the OCSP request that was sent, nil if the OCSP response was not fetched
+	RawRequest     []byte              // the DER encoded OCSP request that was sent, nil if the OCSP response was not fetched
+	HTTP           *HTTPMetadata       // the http response the OCSP response was received in, nil if it was not fetched
+	ResponderURL   string              // the URL of the OCSP responder the OCSP request was sent to, empty if it was not fetched
+	Transport      Transport           // how the OCSP response was obtained
+	Now            time.Time           // the time lints are evaluated at
+	Thresholds     *Thresholds         // the time limits the lints check against
+}
+
 // LintStruct defines the struct of a lint
 type LintStruct struct {
-	Info   string                                                                     // description of the lint
-	Source string                                                                     // source of the lint
-	Exec   func(resp *ocsp.Response, leafCert *x509.Certificate) (LintStatus, string) // the linting function itself
-}
-
-// Lints is the global array of lints that are to be tested (TODO: change to a map)
-var Lints = []*LintStruct{
-	{
-		"Check response signature",
-		"Apple Lints 10 & 12",
-		CheckSignature,
-	},
-	{
-		"Check response producedAt date",
-		"Apple Lints 03 & 05",
-		LintProducedAtDate,
-	},
-	{
-		"Check response thisUpdate date",
-		"Apple Lints 03 & 05",
-		LintThisUpdateDate,
-	},
-	{
-		"Check response nextUpdate date",
-		"Apple Lint 04",
-		LintNextUpdateDate,
-	},
+	ID     string                                      // stable identifier of the lint
+	Info   string                                      // description of the lint
+	Source string                                      // source of the lint
+	Exec   func(ctx *LintContext) (LintStatus, string) // the linting function itself
+}
+
+// Lints is the global array of registered lints, in the order they were registered
+var Lints []*LintStruct
+
+// UnsuccessfulResponseLints are the ids of the lints that are run on OCSP responses whose status is
+// not successful, every other lint is not applicable to them as they have no response to check
+var UnsuccessfulResponseLints = map[string]bool{
+	"e_ocsp_response_status_not_successful":               true,
+	"e_ocsp_response_status_unauthorized_for_served_cert": true,
+	"e_ocsp_unsuccessful_response_has_response_bytes":     true,
+}
+
+// SingleResponseLints are the ids of the lints that check a single SingleResponse, which are also run on
+// every SingleResponse of an OCSP response other than the one Resp was parsed from
+var SingleResponseLints = map[string]bool{
+	"e_ocsp_this_update_too_old":                          true,
+	"e_ocsp_next_update_too_far_after_this_update":        true,
+	"e_ocsp_next_update_missing":                          true,
+	"e_ocsp_validity_interval_too_short":                  true,
+	"e_ocsp_this_update_in_future":                        true,
+	"e_ocsp_this_update_after_produced_at":                true,
+	"e_ocsp_revoked_at_after_this_update":                 true,
+	"e_ocsp_revoked_at_after_produced_at":                 true,
+	"e_ocsp_revocation_reason_invalid":                    true,
+	"e_ocsp_revocation_reason_not_allowed_for_subscriber": true,
+	"e_ocsp_single_extension_critical_unknown":            true,
+	"e_ocsp_archive_cutoff_invalid":                       true,
+	"e_ocsp_crl_references_invalid":                       true,
+}
+
+// lintRegistry maps the ids of registered lints to the lints themselves
+var lintRegistry = make(map[string]*LintStruct)
+
+// RegisterLint adds a lint to the global registry of lints, the lint's id must be unique
+func RegisterLint(lint *LintStruct) error {
+	if lint.ID == "" {
+		return fmt.Errorf("Lint %s has no id", lint.Info)
+	}
+
+	if _, ok := lintRegistry[lint.ID]; ok {
+		return fmt.Errorf("Lint with id %s is already registered", lint.ID)
+	}
+
+	lintRegistry[lint.ID] = lint
+	Lints = append(Lints, lint)
+
+	return nil
+}
+
+// GetLint returns the registered lint with the given id
+func GetLint(id string) (*LintStruct, error) {
+	lint, ok := lintRegistry[id]
+	if !ok {
+		return nil, fmt.Errorf("No lint with id %s is registered", id)
+	}
+
+	return lint, nil
+}
+
+// LintIDs returns the ids of all registered lints in the order they were registered
+func LintIDs() []string {
+	ids := make([]string, len(Lints))
+	for idx, lint := range Lints {
+		ids[idx] = lint.ID
+	}
+
+	return ids
+}
+
+// FilterLints returns the lints out of lints, keeping their order, that
+// - have an id in include, if include is not empty
+// - have a source containing one of sources (ignoring case), if sources is not empty
+// - do not have an id in exclude
+// It errors if include or exclude contain an id that is not registered
+func FilterLints(lints []*LintStruct, include []string, exclude []string, sources []string) ([]*LintStruct, error) {
+	included := make(map[string]bool)
+	for _, id := range include {
+		if _, err := GetLint(id); err != nil {
+			return nil, err
+		}
+		included[id] = true
+	}
+
+	excluded := make(map[string]bool)
+	for _, id := range exclude {
+		if _, err := GetLint(id); err != nil {
+			return nil, err
+		}
+		excluded[id] = true
+	}
+
+	filtered := []*LintStruct{} // not nil, so that filtering out every lint runs no lints
+	for _, lint := range lints {
+		if len(include) > 0 && !included[lint.ID] {
+			continue
+		}
+
+		if len(sources) > 0 && !hasSource(lint, sources) {
+			continue
+		}
+
+		if excluded[lint.ID] {
+			continue
+		}
+
+		filtered = append(filtered, lint)
+	}
+
+	return filtered, nil
+}
+
+// hasSource returns whether the source of a lint contains one of sources, ignoring case
+func hasSource(lint *LintStruct, sources []string) bool {
+	for _, source := range sources {
+		if strings.Contains(strings.ToLower(lint.Source), strings.ToLower(source)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	lints := []*LintStruct{
+		{
+			"e_ocsp_signature_missing_or_sha1",
+			"Check response signature",
+			"Apple Lints 10 & 12",
+			CheckSignature,
+		},
+		{
+			"e_ocsp_signature_algorithm_not_allowed",
+			"Check response signature algorithm is allowed",
+			"CA/B Forum Baseline Requirements Section 7.1.3.2 and Mozilla Root Store Policy Section 5.1",
+			LintSignatureAlgorithmAllowed,
+		},
+		{
+			"e_ocsp_signature_algorithm_does_not_match_key",
+			"Check response signature algorithm matches the signing key type",
+			"RFC 5280 Section 4.1.1.2",
+			LintSignatureAlgorithmMatchesKey,
+		},
+		{
+			"e_ocsp_signing_key_rsa_size_invalid",
+			"Check RSA signing key size",
+			"CA/B Forum Baseline Requirements Section 6.1.5 and Mozilla Root Store Policy Section 5.1",
+			LintSigningKeyRSASize,
+		},
+		{
+			"e_ocsp_signing_key_ecdsa_curve_not_allowed",
+			"Check ECDSA signing key curve",
+			"Mozilla Root Store Policy Section 5.1",
+			LintSigningKeyECDSACurve,
+		},
+		{
+			"e_ocsp_signature_rsa_pss_params_invalid",
+			"Check response RSASSA-PSS parameters",
+			"Mozilla Root Store Policy Section 5.1.1",
+			LintRSAPSSParameters,
+		},
+		{
+			"e_ocsp_produced_at_too_old",
+			"Check response producedAt date",
+			"Apple Lints 03 & 05",
+			LintProducedAtDate,
+		},
+		{
+			"e_ocsp_this_update_too_old",
+			"Check response thisUpdate date",
+			"Apple Lints 03 & 05",
+			LintThisUpdateDate,
+		},
+		{
+			"e_ocsp_next_update_too_far_after_this_update",
+			"Check response nextUpdate date",
+			"Apple Lint 04",
+			LintNextUpdateDate,
+		},
+		{
+			"e_ocsp_next_update_missing",
+			"Check response nextUpdate date is present",
+			"CA/B Forum Baseline Requirements Section 4.9.10",
+			LintNextUpdateMissing,
+		},
+		{
+			"e_ocsp_validity_interval_too_short",
+			"Check response validity interval is not too short",
+			"CA/B Forum Baseline Requirements Section 4.9.10",
+			LintValidityIntervalTooShort,
+		},
+		{
+			"e_ocsp_produced_at_in_future",
+			"Check response producedAt date is not in the future",
+			"RFC 6960 Section 4.2.2.1",
+			LintProducedAtInFuture,
+		},
+		{
+			"e_ocsp_this_update_in_future",
+			"Check response thisUpdate date is not in the future",
+			"RFC 6960 Section 4.2.2.1",
+			LintThisUpdateInFuture,
+		},
+		{
+			"e_ocsp_this_update_after_produced_at",
+			"Check response thisUpdate date is not after producedAt date",
+			"RFC 6960 Section 2.4",
+			LintThisUpdateAfterProducedAt,
+		},
+		{
+			"e_ocsp_responder_cert_missing_ocsp_signing_eku",
+			"Check delegated responder certificate has id-kp-OCSPSigning extended key usage",
+			"RFC 6960 Section 4.2.2.2",
+			LintResponderCertEKU,
+		},
+		{
+			"e_ocsp_responder_cert_not_issued_by_issuer",
+			"Check delegated responder certificate is issued by the issuer",
+			"RFC 6960 Section 4.2.2.2",
+			LintResponderCertIssuer,
+		},
+		{
+			"e_ocsp_responder_cert_not_valid_at_produced_at",
+			"Check delegated responder certificate is valid at producedAt date",
+			"RFC 5280 Section 4.1.2.5",
+			LintResponderCertValidity,
+		},
+		{
+			"e_ocsp_responder_cert_missing_ocsp_nocheck",
+			"Check delegated responder certificate has id-pkix-ocsp-nocheck extension",
+			"CA/B Forum Baseline Requirements Section 4.9.9",
+			LintResponderCertNoCheck,
+		},
+		{
+			"e_ocsp_responder_cert_missing_digital_signature_key_usage",
+			"Check delegated responder certificate has digitalSignature key usage",
+			"RFC 5280 Section 4.2.1.3",
+			LintResponderCertKeyUsage,
+		},
+		{
+			"w_ocsp_responder_cert_lifetime_too_long",
+			"Check delegated responder certificate lifetime is not too long",
+			"RFC 6960 Section 4.2.2.2.1",
+			LintResponderCertLifetime,
+		},
+		{
+			"e_ocsp_responder_id_does_not_match_signer",
+			"Check response ResponderID matches the signer",
+			"RFC 6960 Section 4.2.2.3",
+			LintResponderIDMatchesSigner,
+		},
+		{
+			"w_ocsp_responder_id_not_by_key",
+			"Check response ResponderID uses byKey",
+			"RFC 5019 Section 2.2.3",
+			LintResponderIDByKey,
+		},
+		{
+			"n_ocsp_certs_includes_issuer",
+			"Check response certs field does not include the issuer certificate",
+			"RFC 6960 Section 4.2.1",
+			LintCertsIncludeIssuer,
+		},
+		{
+			"n_ocsp_certs_includes_root",
+			"Check response certs field does not include a root certificate",
+			"RFC 6960 Section 4.2.1",
+			LintCertsIncludeRoot,
+		},
+		{
+			"n_ocsp_certs_multiple",
+			"Check response certs field includes at most one certificate",
+			"RFC 6960 Section 4.2.1",
+			LintCertsMultiple,
+		},
+		{
+			"w_ocsp_certs_cert_did_not_sign",
+			"Check response certs field only includes certificates that signed the response",
+			"RFC 6960 Section 4.2.1",
+			LintCertsDidNotSign,
+		},
+		{
+			"e_ocsp_cert_id_does_not_match_request",
+			"Check response CertID matches the request",
+			"RFC 6960 Section 3.2",
+			LintCertIDMatchesRequest,
+		},
+		{
+			"w_ocsp_single_response_not_requested",
+			"Check response only has SingleResponses for requested certificates",
+			"RFC 6960 Section 4.2.2.3",
+			LintSingleResponseNotRequested,
+		},
+		{
+			"e_ocsp_nonce_does_not_match_request",
+			"Check response nonce matches the request nonce",
+			"RFC 6960 Section 4.4.1",
+			LintNonceMatchesRequest,
+		},
+		{
+			"w_ocsp_nonce_inconsistent_with_caching",
+			"Check response echoes or omits the request nonce consistently with its caching headers",
+			"RFC 5019 Section 6.2",
+			LintNonceCaching,
+		},
+		{
+			"e_ocsp_revoked_at_after_this_update",
+			"Check revoked response revokedAt date is not after thisUpdate date",
+			"RFC 6960 Section 2.4",
+			LintRevokedAtAfterThisUpdate,
+		},
+		{
+			"e_ocsp_revoked_at_after_produced_at",
+			"Check revoked response revokedAt date is not after producedAt date",
+			"RFC 6960 Section 2.4",
+			LintRevokedAtAfterProducedAt,
+		},
+		{
+			"e_ocsp_revocation_reason_invalid",
+			"Check revoked response revocation reason is a valid CRLReason",
+			"RFC 5280 Section 5.3.1",
+			LintRevocationReasonValid,
+		},
+		{
+			"e_ocsp_revocation_reason_not_allowed_for_subscriber",
+			"Check revoked response revocation reason is allowed for subscriber certificates",
+			"CA/B Forum Baseline Requirements Section 7.2.2 and Mozilla Root Store Policy Section 6.1.1",
+			LintRevocationReasonSubscriber,
+		},
+		{
+			"e_ocsp_response_extension_critical_unknown",
+			"Check response has no unrecognized critical response extensions",
+			"RFC 6960 Section 4.4",
+			LintResponseExtensionCriticalUnknown,
+		},
+		{
+			"e_ocsp_single_extension_critical_unknown",
+			"Check response has no unrecognized critical single extensions",
+			"RFC 6960 Section 4.4",
+			LintSingleExtensionCriticalUnknown,
+		},
+		{
+			"e_ocsp_nonce_invalid",
+			"Check response nonce is an OCTET STRING of 1 to 32 bytes",
+			"RFC 8954 Section 2.1",
+			LintNonce,
+		},
+		{
+			"e_ocsp_archive_cutoff_invalid",
+			"Check response archive cutoff is a GeneralizedTime",
+			"RFC 6960 Section 4.4.4",
+			LintArchiveCutoff,
+		},
+		{
+			"e_ocsp_crl_references_invalid",
+			"Check response CRL references are a valid CrlID",
+			"RFC 6960 Section 4.4.2",
+			LintCRLReferences,
+		},
+		{
+			"w_ocsp_response_extension_prevents_caching",
+			"Check response has no extensions that prevent caching",
+			"RFC 5019 Section 2.1",
+			LintResponseExtensionCaching,
+		},
+		{
+			"e_ocsp_response_status_not_successful",
+			"Check response status is successful",
+			"RFC 6960 Section 2.3",
+			LintResponseStatus,
+		},
+		{
+			"e_ocsp_response_status_unauthorized_for_served_cert",
+			"Check responder named by the certificate does not return unauthorized",
+			"CA/B Forum Baseline Requirements Section 4.10.2",
+			LintUnauthorizedForServedCert,
+		},
+		{
+			"e_ocsp_unsuccessful_response_has_response_bytes",
+			"Check unsuccessful response has no response bytes",
+			"RFC 6960 Section 4.2.1",
+			LintUnsuccessfulResponseBytes,
+		},
+		{
+			"e_ocsp_response_type_not_basic",
+			"Check response type is id-pkix-ocsp-basic",
+			"RFC 6960 Section 4.2.1",
+			LintResponseType,
+		},
+		{
+			"e_ocsp_response_version_not_v1",
+			"Check response version is v1",
+			"RFC 6960 Section 4.2.1",
+			LintResponseVersion,
+		},
+		{
+			"e_ocsp_unexpected_fields",
+			"Check response has no unexpected fields",
+			"RFC 6960 Section 4.2.1",
+			LintUnexpectedFields,
+		},
+		{
+			"e_ocsp_der_default_version_encoded",
+			"Check response does not encode its default version",
+			"ITU-T X.690 Section 11.5",
+			LintDefaultVersionEncoded,
+		},
+		{
+			"e_ocsp_der_extension_critical_false_encoded",
+			"Check response extensions do not encode default critical value",
+			"ITU-T X.690 Section 11.5",
+			LintExtensionCriticalEncoding,
+		},
+		{
+			"e_ocsp_der_generalized_time_not_canonical",
+			"Check response GeneralizedTimes are canonical",
+			"ITU-T X.690 Section 11.7",
+			LintGeneralizedTimeEncoding,
+		},
+	}
+
+	for _, lint := range lints {
+		err := RegisterLint(lint)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
 }
 
 // LintStatus defines the possible statuses for a lint
 type LintStatus string
 
+// These mirror the zlint result statuses, with Failed being zlint's Error
+// (a MUST requirement is violated) and Error being zlint's Fatal
 const (
-	Passed LintStatus = "PASSED" // lint passed
-	Failed LintStatus = "FAILED" // lint failed
-	Error  LintStatus = "ERROR"  // encountered error while running lint
+	NotApplicable LintStatus = "NA"     // lint does not apply to this response or certificate
+	NotEffective  LintStatus = "NE"     // lint's requirement was not in effect when the response was produced
+	Passed        LintStatus = "PASSED" // lint passed
+	Notice        LintStatus = "NOTICE" // lint found something worth pointing out that is not a violation
+	Warn          LintStatus = "WARN"   // lint found a violation of a SHOULD requirement
+	Failed        LintStatus = "FAILED" // lint failed, a MUST requirement is violated
+	Error         LintStatus = "ERROR"  // encountered error while running lint
 )
 
+// StatusSeverity ranks lint statuses from least to most severe
+var StatusSeverity = map[LintStatus]int{
+	NotApplicable: 0,
+	NotEffective:  0,
+	Passed:        1,
+	Notice:        2,
+	Warn:          3,
+	Failed:        4,
+	Error:         5,
+}
+
+// Passing returns whether a lint status does not indicate a problem,
+// i.e. it is Passed, NotApplicable or NotEffective
+// A status that is not in StatusSeverity is never passing
+func (s LintStatus) Passing() bool {
+	severity, ok := StatusSeverity[s]
+	return ok && severity <= StatusSeverity[Passed]
+}
+
 // LintResult defines the struct of the result of a Lint
 type LintResult struct {
-	Lint   *LintStruct
-	Status LintStatus
-	Info   string
+	Lint           *LintStruct
+	Status         LintStatus
+	Info           string
+	SingleResponse int      // index of the SingleResponse the result is for, only set along with SerialNumber
+	SerialNumber   *big.Int // serial number of the SingleResponse the result is for, nil if it is the one of the report
+}
+
+// LintReport defines the struct of the results of linting a single OCSP response
+type LintReport struct {
+	Target         string              // the input (server URL or file) the OCSP response came from, set by the caller
+	TargetURI      string              // URI identifying Target, set by the caller
+	ResponseStatus ocsp.ResponseStatus // responseStatus of the OCSP response (see ResponseStatusStrings)
+	RespStatus     int                 // status of the OCSP response (see StatusIntMap), only set if ResponseStatus is successful
+	SerialNumber   *big.Int            // serial number of the certificate the OCSP response is for, only set if ResponseStatus is successful
+	Results        []*LintResult       // results of every lint that was run, in the order they were run
+	StartTime      time.Time           // time at which linting started
+	EvaluatedAt    time.Time           // time the lints were evaluated at, StartTime unless another time was given
+	Duration       time.Duration       // time taken to run all the lints
+}
+
+// Status returns the status of the OCSP response the report is for, or its responseStatus if that is not successful
+func (r *LintReport) Status() string {
+	if r.ResponseStatus != ocsp.Success {
+		return ResponseStatusString(r.ResponseStatus)
+	}
+
+	return StatusIntMap[r.RespStatus]
 }
 
 // LinterInterface is an interface containing the functions that are exported from this file
 type LinterInterface interface {
-	LintOCSPResp(*ocsp.Response, *x509.Certificate, bool)
+	LintOCSPResp(*LintContext) *LintReport
 }
 
 // Linter is a struct of type LinterInterface
-type Linter struct{}
+type Linter struct {
+	Lints      []*LintStruct // lints to run, all registered lints are run if nil
+	Thresholds *Thresholds   // time limits for lints to check against, DefaultThresholds are used if nil
+	Now        time.Time     // time to evaluate lints at, the time linting starts is used if zero
+}
 
-// printResults prints the results of all the lints run
-func printResults(results []*LintResult, verbose bool) {
-	fmt.Println("Printing lint results: ")
-	// sort by status so printing prints all the lints that errored, then failed, then passed
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Status < results[j].Status
-	})
+// LintOCSPResp takes in the context of an OCSP response, lints the response and returns a report of the results
+// Missing parts of the context that can be derived are filled in on a copy of ctx before running the lints:
+// the ASN.1 structure is parsed from RawResp, the responseStatus is taken from it if Resp is nil,
+// Thresholds default to the linter's Thresholds and Now defaults to the linter's Now,
+// falling back to the time linting started
+// Lints that are not in UnsuccessfulResponseLints are not applicable to responses that are not successful,
+// and return Error for successful responses without a parsed Resp
+// Lints in SingleResponseLints are also run on every other SingleResponse of the response, adding a result
+// for each of them after the results of all lints
+func (l Linter) LintOCSPResp(ctx *LintContext) *LintReport {
+	report := &LintReport{
+		StartTime: time.Now(),
+	}
 
-	allPassed := true
+	if ctx.Resp != nil {
+		report.RespStatus = ctx.Resp.Status
+		report.SerialNumber = ctx.Resp.SerialNumber
+	}
 
-	for _, result := range results {
-		if result.Status != Passed {
-			allPassed = false
-		}
-		if result.Status != Passed || verbose {
-			fmt.Printf("%s: %s: %s \n", result.Lint.Info, result.Status, result.Info)
-		}
+	lints := l.Lints
+	if lints == nil {
+		lints = Lints
 	}
 
-	if allPassed {
-		fmt.Println("OCSP Response passed all lints")
+	lintCtx := *ctx
+	if lintCtx.Thresholds == nil {
+		lintCtx.Thresholds = l.Thresholds
+	}
+	if lintCtx.Thresholds == nil {
+		lintCtx.Thresholds = &DefaultThresholds
 	}
-}
 
-// LintOCSPResp takes in a parsed OCSP response and prints its status, and then lints it
-func (l Linter) LintOCSPResp(resp *ocsp.Response, leafCert *x509.Certificate, verbose bool) {
-	fmt.Printf("OCSP Response status: %s \n\n", StatusIntMap[resp.Status])
+	if lintCtx.Now.IsZero() {
+		lintCtx.Now = l.Now
+	}
+	if lintCtx.Now.IsZero() {
+		lintCtx.Now = report.StartTime
+	}
+	report.EvaluatedAt = lintCtx.Now
 
-	var results []*LintResult
-	for _, lint := range Lints {
-		status, info := lint.Exec(resp, leafCert)
-		results = append(results, &LintResult{
+	if lintCtx.ASN1 == nil && lintCtx.RawResp != nil {
+		// lints that need the ASN.1 structure return Error if it could not be parsed
+		lintCtx.ASN1, _ = ParseASN1Response(lintCtx.RawResp)
+	}
+
+	if lintCtx.Resp == nil && lintCtx.ASN1 != nil {
+		lintCtx.ResponseStatus = ocsp.ResponseStatus(lintCtx.ASN1.OCSPResponse.Status)
+	} else if lintCtx.Resp == nil && lintCtx.RawResp != nil {
+		// the responseStatus is still known if only the response bytes could not be parsed
+		if status, err := parseResponseStatus(lintCtx.RawResp); err == nil {
+			lintCtx.ResponseStatus = status
+		}
+	}
+	report.ResponseStatus = lintCtx.ResponseStatus
+
+	if lintCtx.Resp != nil && lintCtx.ASN1 != nil && lintCtx.ASN1.BasicResponse != nil {
+		if idx := singleResponseIndex(lintCtx.ASN1, lintCtx.Resp.SerialNumber); idx >= 0 {
+			lintCtx.SingleResponse = idx
+		}
+	}
+
+	for _, lint := range lints {
+		status, info := NotApplicable, fmt.Sprintf("OCSP Response status is %s, so it has no response to check",
+			ResponseStatusString(lintCtx.ResponseStatus))
+		switch {
+		case UnsuccessfulResponseLints[lint.ID]:
+			status, info = lint.Exec(&lintCtx)
+		case lintCtx.ResponseStatus != ocsp.Success:
+		case lintCtx.Resp == nil:
+			// every other lint needs the parsed response
+			status, info = Error, "OCSP Response could not be parsed, so it cannot be checked"
+		default:
+			status, info = lint.Exec(&lintCtx)
+		}
+		report.Results = append(report.Results, &LintResult{
 			Lint:   lint,
 			Status: status,
 			Info:   info,
 		})
 	}
 
-	printResults(results, verbose)
+	if lintCtx.ResponseStatus == ocsp.Success {
+		report.Results = append(report.Results, lintOtherSingleResponses(&lintCtx, lints)...)
+	}
+
+	report.Duration = time.Since(report.StartTime)
+
+	return report
+}
+
+// lintOtherSingleResponses runs the lints in SingleResponseLints out of lints on every SingleResponse of the
+// OCSP response of ctx other than the one Resp was parsed from, prefixing each result's info with the
+// SingleResponse it is for
+func lintOtherSingleResponses(ctx *LintContext, lints []*LintStruct) []*LintResult {
+	if ctx.RawResp == nil || ctx.ASN1 == nil || ctx.ASN1.BasicResponse == nil {
+		return nil
+	}
+
+	var results []*LintResult
+	for idx, single := range ctx.ASN1.BasicResponse.TBSResponseData.Responses {
+		if idx == ctx.SingleResponse {
+			continue
+		}
+
+		singleCtx := *ctx
+		singleCtx.SingleResponse = idx
+		singleCtx.LeafCert = nil // the certificate the SingleResponse is for is not known
+		resp, err := parseSingleResponse(ctx, idx)
+		singleCtx.Resp = resp
+
+		prefix := fmt.Sprintf("SingleResponse %d (serial number %s): ", idx, single.CertID.SerialNumber)
+		for _, lint := range lints {
+			if !SingleResponseLints[lint.ID] {
+				continue
+			}
+
+			status, info := Error, ""
+			if err != nil {
+				info = fmt.Sprintf("Could not parse the SingleResponse: %s", err.Error())
+			} else {
+				status, info = lint.Exec(&singleCtx)
+			}
+			results = append(results, &LintResult{
+				Lint:           lint,
+				Status:         status,
+				Info:           prefix + info,
+				SingleResponse: idx,
+				SerialNumber:   single.CertID.SerialNumber,
+			})
+		}
+	}
+
+	return results
+}
+
+// parseSingleResponse parses the OCSP response of ctx as if it only had the SingleResponse at index idx
+// ocsp.ParseResponseForCert picks the first SingleResponse with a serial number, so a SingleResponse
+// with the same serial number as an earlier one cannot be parsed
+func parseSingleResponse(ctx *LintContext, idx int) (*ocsp.Response, error) {
+	serial := ctx.ASN1.BasicResponse.TBSResponseData.Responses[idx].CertID.SerialNumber
+	if first := singleResponseIndex(ctx.ASN1, serial); first != idx {
+		return nil, fmt.Errorf("SingleResponse has the same serial number as SingleResponse %d", first)
+	}
+
+	resp, err := ocsp.ParseResponseForCert(ctx.RawResp, &x509.Certificate{SerialNumber: serial}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing SingleResponse: %w", err)
+	}
+
+	return resp, nil
 }
@@ -0,0 +1,341 @@
+package linter
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools"
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// TestLintOCSPResp tests LintOCSPResp, which runs all the lints on an
+// OCSP response and returns a report of the results
+func TestLintOCSPResp(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	report := Linter{}.LintOCSPResp(&LintContext{Resp: ocspResp})
+
+	t.Run("Report contains a result for every lint", func(t *testing.T) {
+		if len(report.Results) != len(Lints) {
+			t.Fatalf("Report should have %d results, instead has %d", len(Lints), len(report.Results))
+		}
+
+		for idx, result := range report.Results {
+			if result.Lint != Lints[idx] {
+				t.Errorf("Result %d should be for lint %s, instead is for lint %s", idx, Lints[idx].Info, result.Lint.Info)
+			}
+		}
+	})
+
+	t.Run("Only runs the linter's lints", func(t *testing.T) {
+		report := Linter{Lints: Lints[1:2]}.LintOCSPResp(&LintContext{Resp: ocspResp})
+		if len(report.Results) != 1 || report.Results[0].Lint != Lints[1] {
+			t.Errorf("Report should only have a result for lint %s", Lints[1].ID)
+		}
+	})
+
+	t.Run("Lints are evaluated at the context's time", func(t *testing.T) {
+		lint, err := GetLint("e_ocsp_produced_at_too_old")
+		if err != nil {
+			panic(err.Error())
+		}
+
+		report := Linter{Lints: []*LintStruct{lint}}.LintOCSPResp(&LintContext{Resp: ocspResp, Now: ocspResp.ProducedAt})
+		if report.Results[0].Status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", report.Results[0].Status, report.Results[0].Info)
+		}
+	})
+
+	t.Run("Lints are evaluated at the linter's time", func(t *testing.T) {
+		lint, err := GetLint("e_ocsp_produced_at_too_old")
+		if err != nil {
+			panic(err.Error())
+		}
+
+		report := Linter{Lints: []*LintStruct{lint}, Now: ocspResp.ProducedAt}.LintOCSPResp(&LintContext{Resp: ocspResp})
+		if report.Results[0].Status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", report.Results[0].Status, report.Results[0].Info)
+		}
+
+		if !report.EvaluatedAt.Equal(ocspResp.ProducedAt) {
+			t.Errorf("Report should have been evaluated at %s, instead was evaluated at %s", ocspResp.ProducedAt, report.EvaluatedAt)
+		}
+	})
+
+	t.Run("Report contains response metadata", func(t *testing.T) {
+		if report.RespStatus != ocspResp.Status {
+			t.Errorf("Report should have response status %s, instead has %s",
+				StatusIntMap[ocspResp.Status], StatusIntMap[report.RespStatus])
+		}
+
+		if report.SerialNumber.Cmp(ocspResp.SerialNumber) != 0 {
+			t.Errorf("Report should have serial number %s, instead has %s", ocspResp.SerialNumber, report.SerialNumber)
+		}
+
+		if report.StartTime.IsZero() {
+			t.Errorf("Report should have a start time")
+		}
+	})
+
+	unauthorizedResp, rawResp, err := ocsptools.Tools{}.ReadOCSPResp(RespUnauthorized)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespUnauthorized, err))
+	}
+
+	t.Run("Unsuccessful response", func(t *testing.T) {
+		report := Linter{}.LintOCSPResp(&LintContext{Resp: unauthorizedResp, RawResp: rawResp})
+		if report.ResponseStatus != ocsp.Unauthorized || report.Status() != "unauthorized" {
+			t.Errorf("Report should have response status unauthorized, instead has %s", report.Status())
+		}
+
+		for _, result := range report.Results {
+			if result.Lint.ID == "e_ocsp_response_status_not_successful" {
+				if result.Status != Failed {
+					t.Errorf("Lint %s should have failed, instead got status %s: %s", result.Lint.ID, result.Status, result.Info)
+				}
+			} else if !UnsuccessfulResponseLints[result.Lint.ID] && result.Status != NotApplicable {
+				t.Errorf("Lint %s should have been not applicable, instead got status %s: %s", result.Lint.ID, result.Status, result.Info)
+			}
+		}
+	})
+
+	// unauthorized response whose basic response bytes cannot be parsed
+	rawBadBasicResp := derSeq(derMarshal(asn1.Enumerated(ocsp.Unauthorized)),
+		derExplicit(0, derSeq(derMarshal(OIDPKIXOCSPBasic), derMarshal([]byte{1}))))
+
+	t.Run("Unsuccessful response with unparsable response bytes", func(t *testing.T) {
+		report := Linter{}.LintOCSPResp(&LintContext{RawResp: rawBadBasicResp})
+		if report.ResponseStatus != ocsp.Unauthorized {
+			t.Errorf("Report should have response status unauthorized, instead has %s", report.Status())
+		}
+
+		for _, result := range report.Results {
+			if !UnsuccessfulResponseLints[result.Lint.ID] && result.Status != NotApplicable {
+				t.Errorf("Lint %s should have been not applicable, instead got status %s: %s", result.Lint.ID, result.Status, result.Info)
+			}
+		}
+	})
+
+	t.Run("Successful response without parsed response", func(t *testing.T) {
+		report := Linter{}.LintOCSPResp(&LintContext{RawResp: []byte{1}})
+		for _, result := range report.Results {
+			if !UnsuccessfulResponseLints[result.Lint.ID] && result.Status != Error {
+				t.Errorf("Lint %s should have errored, instead got status %s: %s", result.Lint.ID, result.Status, result.Info)
+			}
+		}
+	})
+
+	multipleResp, rawMultipleResp, err := ocsptools.Tools{}.ReadOCSPResp(RespMultiple)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespMultiple, err))
+	}
+
+	t.Run("Response with several SingleResponses", func(t *testing.T) {
+		report := Linter{}.LintOCSPResp(&LintContext{Resp: multipleResp, RawResp: rawMultipleResp})
+		if len(report.Results) != len(Lints)+len(SingleResponseLints) {
+			t.Fatalf("Report should have %d results, instead has %d", len(Lints)+len(SingleResponseLints), len(report.Results))
+		}
+
+		for _, result := range report.Results[:len(Lints)] {
+			if result.SerialNumber != nil {
+				t.Errorf("Result of lint %s should be for the SingleResponse of the report", result.Lint.ID)
+			}
+		}
+
+		for _, result := range report.Results[len(Lints):] {
+			if !SingleResponseLints[result.Lint.ID] {
+				t.Errorf("Lint %s should not have been run on the other SingleResponse", result.Lint.ID)
+			}
+
+			if !strings.HasPrefix(result.Info, "SingleResponse 1 (serial number 2): ") || result.SingleResponse != 1 ||
+				result.SerialNumber == nil || result.SerialNumber.Int64() != 2 {
+				t.Errorf("Result of lint %s should be for SingleResponse 1, instead is: %s", result.Lint.ID, result.Info)
+			}
+		}
+	})
+
+	t.Run("Other SingleResponses are linted on their own", func(t *testing.T) {
+		lint, err := GetLint("e_ocsp_this_update_too_old")
+		if err != nil {
+			panic(err.Error())
+		}
+
+		secondResp, err := ocsp.ParseResponseForCert(rawMultipleResp, &x509.Certificate{SerialNumber: big.NewInt(2)}, nil)
+		if err != nil {
+			panic(err.Error())
+		}
+
+		report := Linter{Lints: []*LintStruct{lint}}.LintOCSPResp(&LintContext{Resp: secondResp, RawResp: rawMultipleResp})
+		if len(report.Results) != 2 {
+			t.Fatalf("Report should have 2 results, instead has %d", len(report.Results))
+		}
+
+		if !strings.HasPrefix(report.Results[1].Info, "SingleResponse 0 (serial number 1): ") {
+			t.Errorf("Second result should be for SingleResponse 0, instead is: %s", report.Results[1].Info)
+		}
+	})
+}
+
+// TestLintRegistry tests RegisterLint, GetLint and LintIDs, which manage
+// the global registry of lints
+func TestLintRegistry(t *testing.T) {
+	t.Run("Registered lints have unique ids", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for _, id := range LintIDs() {
+			if id == "" || seen[id] {
+				t.Errorf("Lint id %s is empty or not unique", id)
+			}
+			seen[id] = true
+		}
+	})
+
+	t.Run("Get lint by id", func(t *testing.T) {
+		lint, err := GetLint(Lints[0].ID)
+		if err != nil {
+			t.Fatalf("Got error getting registered lint %s: %s", Lints[0].ID, err.Error())
+		}
+
+		if lint != Lints[0] {
+			t.Errorf("Got wrong lint %s for id %s", lint.ID, Lints[0].ID)
+		}
+	})
+
+	t.Run("Get unknown lint", func(t *testing.T) {
+		_, err := GetLint("e_blah")
+		if err == nil {
+			t.Errorf("Should have gotten error getting unregistered lint")
+		}
+	})
+
+	t.Run("Register duplicate lint", func(t *testing.T) {
+		err := RegisterLint(&LintStruct{ID: Lints[0].ID})
+		if err == nil {
+			t.Errorf("Should have gotten error registering lint with duplicate id")
+		}
+	})
+
+	t.Run("Register lint without id", func(t *testing.T) {
+		err := RegisterLint(&LintStruct{Info: "No id"})
+		if err == nil {
+			t.Errorf("Should have gotten error registering lint without id")
+		}
+	})
+}
+
+// TestResponseStatusString tests ResponseStatusString, which returns the name of a responseStatus value
+func TestResponseStatusString(t *testing.T) {
+	if name := ResponseStatusString(ocsp.Unauthorized); name != "unauthorized" {
+		t.Errorf("Status 6 should be unauthorized, instead got %s", name)
+	}
+
+	if name := ResponseStatusString(4); name != "unknown (4)" {
+		t.Errorf("Status 4 should be unknown (4), instead got %s", name)
+	}
+
+	t.Run("Report with unassigned status", func(t *testing.T) {
+		report := Linter{}.LintOCSPResp(&LintContext{RawResp: derSeq(derMarshal(asn1.Enumerated(4)))})
+		if report.Status() != "unknown (4)" {
+			t.Errorf("Report should have status unknown (4), instead has %s", report.Status())
+		}
+
+		for _, result := range report.Results {
+			if strings.Contains(result.Info, "status is ,") {
+				t.Errorf("Lint %s should name the status: %s", result.Lint.ID, result.Info)
+			}
+		}
+	})
+}
+
+// TestPassing tests Passing, which returns whether a lint status indicates a problem
+func TestPassing(t *testing.T) {
+	for _, status := range []LintStatus{NotApplicable, NotEffective, Passed} {
+		if !status.Passing() {
+			t.Errorf("Status %s should be passing", status)
+		}
+	}
+
+	for _, status := range []LintStatus{Notice, Warn, Failed, Error, "", "PASS"} {
+		if status.Passing() {
+			t.Errorf("Status %s should not be passing", status)
+		}
+	}
+}
+
+// TestFilterLints tests FilterLints, which filters lints by id and source
+func TestFilterLints(t *testing.T) {
+	ids := func(lints []*LintStruct) []string {
+		var ids []string
+		for _, lint := range lints {
+			ids = append(ids, lint.ID)
+		}
+		return ids
+	}
+
+	t.Run("No filters", func(t *testing.T) {
+		lints, err := FilterLints(Lints, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Got error filtering lints: %s", err.Error())
+		}
+
+		if len(lints) != len(Lints) {
+			t.Errorf("Should have gotten all %d lints, instead got %v", len(Lints), ids(lints))
+		}
+	})
+
+	t.Run("Include and exclude lints", func(t *testing.T) {
+		lints, err := FilterLints(Lints, []string{Lints[2].ID, Lints[0].ID, Lints[1].ID}, []string{Lints[1].ID}, nil)
+		if err != nil {
+			t.Fatalf("Got error filtering lints: %s", err.Error())
+		}
+
+		if len(lints) != 2 || lints[0] != Lints[0] || lints[1] != Lints[2] {
+			t.Errorf("Should have gotten lints %s and %s, instead got %v", Lints[0].ID, Lints[2].ID, ids(lints))
+		}
+	})
+
+	t.Run("Include sources", func(t *testing.T) {
+		lints, err := FilterLints(Lints, nil, nil, []string{"apple lint 04"})
+		if err != nil {
+			t.Fatalf("Got error filtering lints: %s", err.Error())
+		}
+
+		for _, lint := range lints {
+			if lint.Source != "Apple Lint 04" {
+				t.Errorf("Should not have gotten lint %s from source %s", lint.ID, lint.Source)
+			}
+		}
+
+		if len(lints) == 0 {
+			t.Errorf("Should have gotten lints from source Apple Lint 04")
+		}
+	})
+
+	t.Run("Exclude every lint", func(t *testing.T) {
+		lints, err := FilterLints(Lints, nil, LintIDs(), nil)
+		if err != nil {
+			t.Fatalf("Got error filtering lints: %s", err.Error())
+		}
+
+		if lints == nil || len(lints) != 0 {
+			t.Errorf("Should have gotten an empty list of lints, instead got %v", ids(lints))
+		}
+	})
+
+	t.Run("Unknown lint ids", func(t *testing.T) {
+		_, err := FilterLints(Lints, []string{"e_blah"}, nil, nil)
+		if err == nil {
+			t.Errorf("Should have gotten error including unknown lint")
+		}
+
+		_, err = FilterLints(Lints, nil, []string{"e_blah"}, nil)
+		if err == nil {
+			t.Errorf("Should have gotten error excluding unknown lint")
+		}
+	})
+}
@@ -3,28 +3,41 @@ package linter
 import (
 	"crypto/x509"
 	"fmt"
-	"golang.org/x/crypto/ocsp"
 	"time"
 )
 
 const (
-	ProducedAtLimitSubscriber = "96h"   // 4 days
-	ThisUpdateLimitSubscriber = "96h"   // 4 days
-	ProducedAtLimitCA         = "8760h" // 365 days
-	ThisUpdateLimitCA         = "8760h" // 365 days
-	NextUpdateLimitSubscriber = "240h"  // 10 days
+	ProducedAtLimitSubscriber   = 96 * time.Hour   // 4 days
+	ThisUpdateLimitSubscriber   = 96 * time.Hour   // 4 days
+	ProducedAtLimitCA           = 8760 * time.Hour // 365 days
+	ThisUpdateLimitCA           = 8760 * time.Hour // 365 days
+	NextUpdateLimitSubscriber   = 240 * time.Hour  // 10 days
+	ClockSkewTolerance          = 5 * time.Minute  // 5 minutes
+	NextUpdateMinimumSubscriber = 8 * time.Hour    // 8 hours
 )
 
-// DurationToString is a map mapping durations to more readable strings
-var DurationToString = map[string]string{
-	ProducedAtLimitSubscriber: "4 days",
-	ProducedAtLimitCA:         "365 days",
-	NextUpdateLimitSubscriber: "10 days",
+// DurationToString converts a duration to a more readable string, e.g. 96h to 4 days
+func DurationToString(d time.Duration) string {
+	day := 24 * time.Hour
+	switch {
+	case d == day:
+		return "1 day"
+	case d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	case d == time.Hour:
+		return "1 hour"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	default:
+		return d.String()
+	}
 }
 
 // CheckSignature checks in the ocsp response is signed with an algorithm that uses SHA1
 // Source: Apple Lints 10 & 12
-func CheckSignature(resp *ocsp.Response, leafCert *x509.Certificate) (LintStatus, string) {
+func CheckSignature(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
 	if resp.Signature == nil || len(resp.Signature) == 0 {
 		return Failed, "OCSP Response is not signed"
 	}
@@ -39,78 +52,162 @@ func CheckSignature(resp *ocsp.Response, leafCert *x509.Certificate) (LintStatus
 	return Passed, "OCSP Response is signed with an algorithm that does not use SHA1"
 }
 
-// LintProducedAtDate checks that an OCSP Response ProducedAt date is no more than ProducedAtLimit in the past
+// LintProducedAtDate checks that an OCSP Response ProducedAt date is no more than the ProducedAt limit in the past
 // Source: Apple Lints 03 & 05
-func LintProducedAtDate(resp *ocsp.Response, leafCert *x509.Certificate) (LintStatus, string) {
+func LintProducedAtDate(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
 	// default assume certificate being checked is a subscriber certificate
 	certType := "subscriber certificate"
-	producedAtLimit := ProducedAtLimitSubscriber
-	if leafCert != nil && leafCert.IsCA {
+	limit := ctx.Thresholds.ProducedAtLimitSubscriber
+	if ctx.LeafCert != nil && ctx.LeafCert.IsCA {
 		certType = "subordinate CA certificate"
-		producedAtLimit = ProducedAtLimitCA
-	}
-
-	limit, err := time.ParseDuration(producedAtLimit)
-
-	if err != nil {
-		return Error, fmt.Sprintf("Could not parse time duration %s", producedAtLimit)
+		limit = ctx.Thresholds.ProducedAtLimitCA
 	}
 
-	if time.Since(resp.ProducedAt) > limit {
+	if ctx.Now.Sub(resp.ProducedAt) > limit {
 		return Failed, fmt.Sprintf("OCSP Response producedAt date %s for %s is more than %s in the past",
-			resp.ProducedAt, certType, DurationToString[producedAtLimit])
+			resp.ProducedAt, certType, DurationToString(limit))
 	}
 
 	return Passed, fmt.Sprintf("OCSP Response producedAt date %s for %s is within %s in the past",
-		resp.ProducedAt, certType, DurationToString[producedAtLimit])
+		resp.ProducedAt, certType, DurationToString(limit))
 }
 
-// LintThisUpdateDate checks that an OCSP Response ThisUpdate date is no more than ThisUpdateLimit in the past
+// LintThisUpdateDate checks that an OCSP Response ThisUpdate date is no more than the ThisUpdate limit in the past
 // Source: Apple Lints 03 & 05
-func LintThisUpdateDate(resp *ocsp.Response, leafCert *x509.Certificate) (LintStatus, string) {
+func LintThisUpdateDate(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
 	// default assume certificate being checked is a subscriber certificate
 	certType := "subscriber certificate"
-	thisUpdateLimit := ThisUpdateLimitSubscriber
-	if leafCert != nil && leafCert.IsCA {
+	limit := ctx.Thresholds.ThisUpdateLimitSubscriber
+	if ctx.LeafCert != nil && ctx.LeafCert.IsCA {
 		certType = "subordinate CA certificate"
-		thisUpdateLimit = ThisUpdateLimitCA
-	}
-
-	limit, err := time.ParseDuration(thisUpdateLimit)
-	if err != nil {
-		return Error, fmt.Sprintf("Could not parse time duration %s", thisUpdateLimit)
+		limit = ctx.Thresholds.ThisUpdateLimitCA
 	}
 
-	if time.Since(resp.ThisUpdate) > limit {
+	if ctx.Now.Sub(resp.ThisUpdate) > limit {
 		return Failed, fmt.Sprintf("OCSP Response thisUpdate date %s for %s is more than %s in the past",
-			resp.ThisUpdate, certType, DurationToString[thisUpdateLimit])
+			resp.ThisUpdate, certType, DurationToString(limit))
 
 	}
 
 	return Passed, fmt.Sprintf("OCSP Response thisUpdate date %s for %s is within %s in the past",
-		resp.ThisUpdate, certType, DurationToString[thisUpdateLimit])
+		resp.ThisUpdate, certType, DurationToString(limit))
 
 }
 
-// LintNextUpdateDate checks that an OCSP Response NextUpdate date is no more than NextUpdateLimitSubscriber in the past
+// LintNextUpdateDate checks that an OCSP Response NextUpdate date is no more than the NextUpdate limit after its ThisUpdate date
 // Source: Apple Lint 04
-func LintNextUpdateDate(resp *ocsp.Response, leafCert *x509.Certificate) (LintStatus, string) {
-	if leafCert != nil && leafCert.IsCA {
-		return Passed, "OCSP Response nextUpdate lint not applicable to CA certificates"
+func LintNextUpdateDate(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	if ctx.LeafCert != nil && ctx.LeafCert.IsCA {
+		return NotApplicable, "OCSP Response nextUpdate lint not applicable to CA certificates"
 	}
 
-	limit, err := time.ParseDuration(NextUpdateLimitSubscriber)
-	if err != nil {
-		return Error, fmt.Sprintf("Could not parse time duration %s", NextUpdateLimitSubscriber)
+	if resp.NextUpdate.IsZero() {
+		return NotApplicable, "OCSP Response has no NextUpdate date"
 	}
 
+	limit := ctx.Thresholds.NextUpdateLimitSubscriber
+
 	if resp.NextUpdate.Sub(resp.ThisUpdate) > limit {
 		return Failed, fmt.Sprintf("OCSP Response NextUpdate date %s is more than %s after ThisUpdate date %s",
-			resp.NextUpdate, DurationToString[NextUpdateLimitSubscriber], resp.ThisUpdate)
+			resp.NextUpdate, DurationToString(limit), resp.ThisUpdate)
 
 	}
 
 	return Passed, fmt.Sprintf("OCSP Response NextUpdate date %s is within %s after ThisUpdate date %s",
-		resp.NextUpdate, DurationToString[NextUpdateLimitSubscriber], resp.ThisUpdate)
+		resp.NextUpdate, DurationToString(limit), resp.ThisUpdate)
+
+}
+
+// LintNextUpdateMissing checks that an OCSP Response for a subscriber certificate has a NextUpdate date
+// Source: CA/B Forum Baseline Requirements Section 4.9.10
+func LintNextUpdateMissing(ctx *LintContext) (LintStatus, string) {
+	if ctx.LeafCert != nil && ctx.LeafCert.IsCA {
+		return NotApplicable, "OCSP Response nextUpdate lint not applicable to CA certificates"
+	}
+
+	if ctx.Resp.NextUpdate.IsZero() {
+		return Failed, "OCSP Response for subscriber certificate has no NextUpdate date"
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response for subscriber certificate has NextUpdate date %s", ctx.Resp.NextUpdate)
+}
+
+// LintValidityIntervalTooShort checks that the validity interval of an OCSP Response, from its
+// ThisUpdate date to its NextUpdate date inclusive, is at least the NextUpdate minimum
+// Source: CA/B Forum Baseline Requirements Section 4.9.10
+func LintValidityIntervalTooShort(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	if ctx.LeafCert != nil && ctx.LeafCert.IsCA {
+		return NotApplicable, "OCSP Response validity interval lint not applicable to CA certificates"
+	}
+
+	if resp.NextUpdate.IsZero() {
+		return NotApplicable, "OCSP Response has no NextUpdate date"
+	}
+
+	minimum := ctx.Thresholds.NextUpdateMinimumSubscriber
+
+	// the validity interval includes both the thisUpdate and nextUpdate seconds
+	interval := resp.NextUpdate.Sub(resp.ThisUpdate) + time.Second
+	if interval < minimum {
+		return Failed, fmt.Sprintf("OCSP Response validity interval %s from ThisUpdate date %s to NextUpdate date %s is less than %s",
+			interval, resp.ThisUpdate, resp.NextUpdate, DurationToString(minimum))
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response validity interval %s from ThisUpdate date %s to NextUpdate date %s is at least %s",
+		interval, resp.ThisUpdate, resp.NextUpdate, DurationToString(minimum))
+}
+
+// LintProducedAtInFuture checks that an OCSP Response ProducedAt date is not in the future,
+// allowing for the clock skew tolerance
+// Source: RFC 6960 Section 4.2.2.1
+func LintProducedAtInFuture(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+	tolerance := ctx.Thresholds.ClockSkewTolerance
+
+	if resp.ProducedAt.Sub(ctx.Now) > tolerance {
+		return Failed, fmt.Sprintf("OCSP Response producedAt date %s is more than %s in the future",
+			resp.ProducedAt, DurationToString(tolerance))
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response producedAt date %s is not more than %s in the future",
+		resp.ProducedAt, DurationToString(tolerance))
+}
+
+// LintThisUpdateInFuture checks that an OCSP Response ThisUpdate date is not in the future,
+// allowing for the clock skew tolerance, as clients consider such responses unreliable
+// Source: RFC 6960 Section 4.2.2.1
+func LintThisUpdateInFuture(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+	tolerance := ctx.Thresholds.ClockSkewTolerance
+
+	if resp.ThisUpdate.Sub(ctx.Now) > tolerance {
+		return Failed, fmt.Sprintf("OCSP Response thisUpdate date %s is more than %s in the future",
+			resp.ThisUpdate, DurationToString(tolerance))
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response thisUpdate date %s is not more than %s in the future",
+		resp.ThisUpdate, DurationToString(tolerance))
+}
+
+// LintThisUpdateAfterProducedAt checks that an OCSP Response ThisUpdate date is not after its
+// ProducedAt date, as the status can't be known to be correct later than the response was signed
+// Source: RFC 6960 Section 2.4
+func LintThisUpdateAfterProducedAt(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	if resp.ThisUpdate.After(resp.ProducedAt) {
+		return Failed, fmt.Sprintf("OCSP Response thisUpdate date %s is after its producedAt date %s",
+			resp.ThisUpdate, resp.ProducedAt)
+	}
 
+	return Passed, fmt.Sprintf("OCSP Response thisUpdate date %s is not after its producedAt date %s",
+		resp.ThisUpdate, resp.ProducedAt)
 }
@@ -0,0 +1,139 @@
+package linter
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// getEmbeddedCerts returns the certificates in the certs field of the OCSP response being linted,
+// or nil and the status and info the lint should return if they are not available
+func getEmbeddedCerts(ctx *LintContext) ([]*x509.Certificate, LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return nil, status, info
+	}
+
+	certs := []*x509.Certificate{}
+	for idx, raw := range basicResp.Certs {
+		cert, err := x509.ParseCertificate(raw.FullBytes)
+		if err != nil {
+			return nil, Error, fmt.Sprintf("Could not parse certificate %d of the OCSP Response: %s", idx, err.Error())
+		}
+
+		certs = append(certs, cert)
+	}
+
+	return certs, "", ""
+}
+
+// isSelfSigned returns whether a certificate is signed by its own key, as a root certificate is
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// certToString returns a description of a certificate for lint infos
+func certToString(cert *x509.Certificate) string {
+	return fmt.Sprintf("certificate %q", cert.Subject.String())
+}
+
+// LintCertsIncludeIssuer checks that the certs field of an OCSP Response does not include
+// the issuer certificate, which the client already has to have to check the response
+// Source: RFC 6960 Section 4.2.1
+func LintCertsIncludeIssuer(ctx *LintContext) (LintStatus, string) {
+	certs, status, info := getEmbeddedCerts(ctx)
+	if certs == nil {
+		return status, info
+	}
+
+	if ctx.IssuerCert == nil {
+		return NotApplicable, "Issuer certificate is not available"
+	}
+
+	for _, cert := range certs {
+		if bytes.Equal(cert.Raw, ctx.IssuerCert.Raw) {
+			return Notice, fmt.Sprintf("OCSP Response certs field includes the issuer %s, which is unnecessary",
+				certToString(cert))
+		}
+	}
+
+	return Passed, "OCSP Response certs field does not include the issuer certificate"
+}
+
+// LintCertsIncludeRoot checks that the certs field of an OCSP Response does not include a
+// self signed root certificate, which clients only trust if they already have it
+// Source: RFC 6960 Section 4.2.1
+func LintCertsIncludeRoot(ctx *LintContext) (LintStatus, string) {
+	certs, status, info := getEmbeddedCerts(ctx)
+	if certs == nil {
+		return status, info
+	}
+
+	for _, cert := range certs {
+		if isSelfSigned(cert) {
+			return Notice, fmt.Sprintf("OCSP Response certs field includes the self signed root %s, which is unnecessary",
+				certToString(cert))
+		}
+	}
+
+	return Passed, "OCSP Response certs field does not include a root certificate"
+}
+
+// LintCertsMultiple checks that the certs field of an OCSP Response includes at most one certificate,
+// as only the delegated responder certificate is needed to check the response
+// Source: RFC 6960 Section 4.2.1
+func LintCertsMultiple(ctx *LintContext) (LintStatus, string) {
+	certs, status, info := getEmbeddedCerts(ctx)
+	if certs == nil {
+		return status, info
+	}
+
+	if len(certs) > 1 {
+		return Notice, fmt.Sprintf("OCSP Response certs field includes %d certificates where one suffices", len(certs))
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response certs field includes %d certificates", len(certs))
+}
+
+// LintCertsDidNotSign checks that every certificate in the certs field of an OCSP Response, other
+// than the issuer and root certificates, has the key that signed the response, as any other
+// certificate doesn't help the client check the signature of the response
+// Source: RFC 6960 Section 4.2.1
+func LintCertsDidNotSign(ctx *LintContext) (LintStatus, string) {
+	certs, status, info := getEmbeddedCerts(ctx)
+	if certs == nil {
+		return status, info
+	}
+
+	resp := ctx.Resp
+	checked := 0
+	for _, cert := range certs {
+		// the issuer and root certificates are reported by LintCertsIncludeIssuer and LintCertsIncludeRoot
+		if (ctx.IssuerCert != nil && bytes.Equal(cert.Raw, ctx.IssuerCert.Raw)) || isSelfSigned(cert) {
+			continue
+		}
+
+		err := cert.CheckSignature(resp.SignatureAlgorithm, resp.TBSResponseData, resp.Signature)
+		var insecureErr x509.InsecureAlgorithmError
+		if errors.Is(err, x509.ErrUnsupportedAlgorithm) || errors.As(err, &insecureErr) {
+			return Error, fmt.Sprintf("Could not check the OCSP Response signature with %s: %s",
+				certToString(cert), err.Error())
+		}
+		if err != nil {
+			return Warn, fmt.Sprintf("OCSP Response certs field includes %s, which did not sign the response",
+				certToString(cert))
+		}
+		checked++
+	}
+
+	if checked == 0 {
+		return NotApplicable, "OCSP Response certs field includes no certificate that could have signed it"
+	}
+
+	return Passed, "OCSP Response certs field only includes certificates that signed the response"
+}
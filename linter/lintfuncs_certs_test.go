@@ -0,0 +1,175 @@
+package linter
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newCertsLintContext returns a lint context of an OCSP response for a certificate issued by issuer,
+// signed with signerKey and whose certs field includes certs
+func newCertsLintContext(issuer *x509.Certificate, signerKey crypto.Signer, certs ...*x509.Certificate) *LintContext {
+	ctx := newCreatedLintContext(ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: big.NewInt(1),
+		ThisUpdate:   time.Now().Add(-time.Hour),
+		NextUpdate:   time.Now().Add(24 * time.Hour),
+	}, issuer, signerKey)
+	for _, cert := range certs {
+		ctx.ASN1.BasicResponse.Certs = append(ctx.ASN1.BasicResponse.Certs, asn1.RawValue{FullBytes: cert.Raw})
+	}
+	return ctx
+}
+
+// newTestIntermediate creates a CA certificate issued by a newly created root
+// and returns it along with its key and the root
+func newTestIntermediate() (*x509.Certificate, crypto.Signer, *x509.Certificate) {
+	root, rootKey := newTestIssuer("Test Root")
+	intermediate, key := newTestCert(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test Intermediate"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, root, rootKey)
+
+	return intermediate, key, root
+}
+
+// TestGetEmbeddedCerts tests getEmbeddedCerts, which returns the certificates in the certs field
+func TestGetEmbeddedCerts(t *testing.T) {
+	issuer, issuerKey, root := newTestIntermediate()
+
+	t.Run("Happy path", func(t *testing.T) {
+		certs, status, info := getEmbeddedCerts(newCertsLintContext(issuer, issuerKey, issuer, root))
+		if len(certs) != 2 {
+			t.Errorf("Should have gotten 2 certificates, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx := newCertsLintContext(issuer, issuerKey)
+	ctx.ASN1.BasicResponse.Certs = []asn1.RawValue{{FullBytes: []byte{1, 2, 3}}}
+	t.Run("Bad certificate", func(t *testing.T) {
+		_, status, info := getEmbeddedCerts(ctx)
+		if status != Error {
+			t.Errorf("Should have gotten status %s, instead got status %s: %s", Error, status, info)
+		}
+	})
+}
+
+// TestLintCertsIncludeIssuer tests LintCertsIncludeIssuer, which checks that the certs
+// field of an OCSP Response does not include the issuer certificate
+// Source: RFC 6960 Section 4.2.1
+func TestLintCertsIncludeIssuer(t *testing.T) {
+	issuer, issuerKey, _ := newTestIntermediate()
+	responder, responderKey := newTestCert(newResponderTemplate(), issuer, issuerKey)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintCertsIncludeIssuer(newCertsLintContext(issuer, responderKey, responder))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx := newCertsLintContext(issuer, issuerKey, issuer)
+	ctx.IssuerCert = nil
+	t.Run("No issuer certificate", func(t *testing.T) {
+		status, info := LintCertsIncludeIssuer(ctx)
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Issuer included", func(t *testing.T) {
+		status, info := LintCertsIncludeIssuer(newCertsLintContext(issuer, issuerKey, issuer))
+		if status != Notice {
+			t.Errorf("Lint should have given notice, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintCertsIncludeRoot tests LintCertsIncludeRoot, which checks that the certs
+// field of an OCSP Response does not include a root certificate
+// Source: RFC 6960 Section 4.2.1
+func TestLintCertsIncludeRoot(t *testing.T) {
+	issuer, issuerKey, root := newTestIntermediate()
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintCertsIncludeRoot(newCertsLintContext(issuer, issuerKey, issuer))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Root included", func(t *testing.T) {
+		status, info := LintCertsIncludeRoot(newCertsLintContext(issuer, issuerKey, issuer, root))
+		if status != Notice {
+			t.Errorf("Lint should have given notice, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintCertsMultiple tests LintCertsMultiple, which checks that the certs
+// field of an OCSP Response includes at most one certificate
+// Source: RFC 6960 Section 4.2.1
+func TestLintCertsMultiple(t *testing.T) {
+	issuer, issuerKey, _ := newTestIntermediate()
+	responder, responderKey := newTestCert(newResponderTemplate(), issuer, issuerKey)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintCertsMultiple(newCertsLintContext(issuer, responderKey, responder))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No raw response", func(t *testing.T) {
+		status, info := LintCertsMultiple(newLintContext(&ocsp.Response{}, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Multiple certificates", func(t *testing.T) {
+		status, info := LintCertsMultiple(newCertsLintContext(issuer, responderKey, responder, issuer))
+		if status != Notice {
+			t.Errorf("Lint should have given notice, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintCertsDidNotSign tests LintCertsDidNotSign, which checks that the certs field of
+// an OCSP Response only includes certificates that signed the response
+// Source: RFC 6960 Section 4.2.1
+func TestLintCertsDidNotSign(t *testing.T) {
+	issuer, issuerKey, root := newTestIntermediate()
+	responder, responderKey := newTestCert(newResponderTemplate(), issuer, issuerKey)
+	otherResponder, _ := newTestCert(newResponderTemplate(), issuer, issuerKey)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintCertsDidNotSign(newCertsLintContext(issuer, responderKey, responder, issuer, root))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No certificates", func(t *testing.T) {
+		status, info := LintCertsDidNotSign(newCertsLintContext(issuer, issuerKey))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Certificate did not sign", func(t *testing.T) {
+		status, info := LintCertsDidNotSign(newCertsLintContext(issuer, responderKey, otherResponder))
+		if status != Warn {
+			t.Errorf("Lint should have warned, instead got status %s: %s", status, info)
+		}
+	})
+}
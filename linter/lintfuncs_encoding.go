@@ -0,0 +1,303 @@
+package linter
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"strings"
+)
+
+const (
+	TagEnumerated      = 10 // universal ASN.1 tag of ENUMERATED
+	TagGeneralizedTime = 24 // universal ASN.1 tag of GeneralizedTime
+)
+
+// elemSpec defines the struct of an element expected in a SEQUENCE
+type elemSpec struct {
+	class    int   // ASN.1 class of the element
+	tags     []int // possible tags of the element, more than one for a CHOICE
+	optional bool  // whether the element is OPTIONAL or has a DEFAULT
+	name     string
+}
+
+// matches returns whether elem is of the class and one of the tags of the spec
+func (spec elemSpec) matches(elem asn1.RawValue) bool {
+	if elem.Class != spec.class {
+		return false
+	}
+
+	for _, tag := range spec.tags {
+		if elem.Tag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The expected elements of the SEQUENCEs of an OCSP response, see RFC 6960 section 4.2.1
+var (
+	ocspResponseSpec = []elemSpec{
+		{asn1.ClassUniversal, []int{TagEnumerated}, false, "responseStatus"},
+		{asn1.ClassContextSpecific, []int{0}, true, "responseBytes"},
+	}
+	basicResponseSpec = []elemSpec{
+		{asn1.ClassUniversal, []int{asn1.TagSequence}, false, "tbsResponseData"},
+		{asn1.ClassUniversal, []int{asn1.TagSequence}, false, "signatureAlgorithm"},
+		{asn1.ClassUniversal, []int{asn1.TagBitString}, false, "signature"},
+		{asn1.ClassContextSpecific, []int{0}, true, "certs"},
+	}
+	responseDataSpec = []elemSpec{
+		{asn1.ClassContextSpecific, []int{0}, true, "version"},
+		{asn1.ClassContextSpecific, []int{1, 2}, false, "responderID"},
+		{asn1.ClassUniversal, []int{TagGeneralizedTime}, false, "producedAt"},
+		{asn1.ClassUniversal, []int{asn1.TagSequence}, false, "responses"},
+		{asn1.ClassContextSpecific, []int{1}, true, "responseExtensions"},
+	}
+	singleResponseSpec = []elemSpec{
+		{asn1.ClassUniversal, []int{asn1.TagSequence}, false, "certID"},
+		{asn1.ClassContextSpecific, []int{0, 1, 2}, false, "certStatus"},
+		{asn1.ClassUniversal, []int{TagGeneralizedTime}, false, "thisUpdate"},
+		{asn1.ClassContextSpecific, []int{0}, true, "nextUpdate"},
+		{asn1.ClassContextSpecific, []int{1}, true, "singleExtensions"},
+	}
+)
+
+// unexpectedElements returns the number of elements of the DER encoded SEQUENCE der
+// that are not expected according to specs
+func unexpectedElements(der []byte, specs []elemSpec) (int, error) {
+	var seq asn1.RawValue
+	_, err := asn1.Unmarshal(der, &seq)
+	if err != nil {
+		return 0, err
+	}
+
+	elems, err := sequenceElements(seq.Bytes)
+	if err != nil {
+		return 0, err
+	}
+
+	idx := 0
+	for _, spec := range specs {
+		if idx < len(elems) && spec.matches(elems[idx]) {
+			idx++
+		} else if !spec.optional {
+			return 0, fmt.Errorf("Missing %s", spec.name)
+		}
+	}
+
+	return len(elems) - idx, nil
+}
+
+// getBasicResponse returns the ASN.1 structure of the basic OCSP response being linted,
+// or nil and the status and info the lint should return if it is not available
+func getBasicResponse(ctx *LintContext) (*RawBasicResponse, LintStatus, string) {
+	if ctx.RawResp == nil {
+		return nil, NotApplicable, "Raw OCSP Response is not available"
+	}
+
+	if ctx.ASN1 == nil || ctx.ASN1.BasicResponse == nil {
+		return nil, Error, "Could not parse the ASN.1 structure of the OCSP Response"
+	}
+
+	return ctx.ASN1.BasicResponse, "", ""
+}
+
+// LintResponseType checks that the responseType of an OCSP Response is id-pkix-ocsp-basic
+// Source: RFC 6960 Section 4.2.1
+func LintResponseType(ctx *LintContext) (LintStatus, string) {
+	if ctx.RawResp == nil {
+		return NotApplicable, "Raw OCSP Response is not available"
+	}
+
+	if ctx.ASN1 == nil {
+		return Error, "Could not parse the ASN.1 structure of the OCSP Response"
+	}
+
+	responseType := ctx.ASN1.OCSPResponse.ResponseBytes.ResponseType
+	if !responseType.Equal(OIDPKIXOCSPBasic) {
+		return Failed, fmt.Sprintf("OCSP Response has response type %s instead of id-pkix-ocsp-basic", responseType)
+	}
+
+	return Passed, "OCSP Response has response type id-pkix-ocsp-basic"
+}
+
+// LintResponseVersion checks that the version of an OCSP Response is v1, the only version defined
+// Source: RFC 6960 Section 4.2.1
+func LintResponseVersion(ctx *LintContext) (LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	version := basicResp.TBSResponseData.Version
+	if len(version.FullBytes) == 0 {
+		return Passed, "OCSP Response has the default version v1"
+	}
+
+	var v int
+	rest, err := asn1.Unmarshal(version.Bytes, &v)
+	if err != nil || len(rest) > 0 {
+		return Failed, "OCSP Response version is not an INTEGER"
+	}
+
+	if v != 0 {
+		return Failed, fmt.Sprintf("OCSP Response has version v%d instead of v1", v+1)
+	}
+
+	return Passed, "OCSP Response has version v1"
+}
+
+// LintDefaultVersionEncoded checks that the version of an OCSP Response is not encoded when it
+// is the DEFAULT v1, as DER requires DEFAULT values to be omitted
+// Source: ITU-T X.690 Section 11.5
+func LintDefaultVersionEncoded(ctx *LintContext) (LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	version := basicResp.TBSResponseData.Version
+	if len(version.FullBytes) > 0 && len(version.Bytes) == 3 && version.Bytes[0] == asn1.TagInteger && version.Bytes[2] == 0 {
+		return Failed, "OCSP Response encodes its DEFAULT version v1, which DER requires to be omitted"
+	}
+
+	return Passed, "OCSP Response does not encode its DEFAULT version"
+}
+
+// LintUnexpectedFields checks that the SEQUENCEs of an OCSP Response contain no elements other
+// than those defined, which ocsp.ParseResponse silently ignores
+// Source: RFC 6960 Section 4.2.1
+func LintUnexpectedFields(ctx *LintContext) (LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	seqs := []struct {
+		name  string
+		der   []byte
+		specs []elemSpec
+	}{
+		{"OCSPResponse", ctx.ASN1.OCSPResponse.Raw, ocspResponseSpec},
+		{"BasicOCSPResponse", basicResp.Raw, basicResponseSpec},
+		{"ResponseData", basicResp.TBSResponseData.Raw, responseDataSpec},
+	}
+	for idx, singleResp := range basicResp.TBSResponseData.Responses {
+		seqs = append(seqs, struct {
+			name  string
+			der   []byte
+			specs []elemSpec
+		}{fmt.Sprintf("SingleResponse %d", idx), singleResp.Raw, singleResponseSpec})
+	}
+
+	var unexpected []string
+	for _, seq := range seqs {
+		count, err := unexpectedElements(seq.der, seq.specs)
+		if err != nil {
+			return Error, fmt.Sprintf("Could not check the elements of %s: %s", seq.name, err.Error())
+		}
+
+		if count > 0 {
+			unexpected = append(unexpected, fmt.Sprintf("%d in %s", count, seq.name))
+		}
+	}
+
+	if len(unexpected) > 0 {
+		return Failed, fmt.Sprintf("OCSP Response has unexpected fields: %s", strings.Join(unexpected, ", "))
+	}
+
+	return Passed, "OCSP Response has no unexpected fields"
+}
+
+// LintGeneralizedTimeEncoding checks that every GeneralizedTime in the ResponseData of an
+// OCSP Response is in the canonical DER form YYYYMMDDHHMMSS[.f]Z without trailing zeros
+// Source: ITU-T X.690 Section 11.7
+func LintGeneralizedTimeEncoding(ctx *LintContext) (LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	var badTimes []string
+	err := walkDER(basicResp.TBSResponseData.Raw, func(elem asn1.RawValue) {
+		if elem.Class == asn1.ClassUniversal && elem.Tag == TagGeneralizedTime && !isCanonicalGeneralizedTime(string(elem.Bytes)) {
+			badTimes = append(badTimes, string(elem.Bytes))
+		}
+	})
+	if err != nil {
+		return Error, fmt.Sprintf("Could not walk the ASN.1 structure of the OCSP Response: %s", err.Error())
+	}
+
+	if len(badTimes) > 0 {
+		return Failed, fmt.Sprintf("OCSP Response has GeneralizedTimes not in canonical DER form: %s", strings.Join(badTimes, ", "))
+	}
+
+	return Passed, "OCSP Response GeneralizedTimes are in canonical DER form"
+}
+
+// isCanonicalGeneralizedTime returns whether a GeneralizedTime is of the form YYYYMMDDHHMMSS[.f]Z
+// with no trailing zeros in the fractional seconds
+func isCanonicalGeneralizedTime(t string) bool {
+	if len(t) < 15 || !strings.HasSuffix(t, "Z") {
+		return false
+	}
+
+	for _, c := range t[:14] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	fraction := t[14 : len(t)-1]
+	if fraction == "" {
+		return true
+	}
+
+	if len(fraction) < 2 || fraction[0] != '.' || strings.HasSuffix(fraction, "0") {
+		return false
+	}
+
+	for _, c := range fraction[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LintExtensionCriticalEncoding checks that no extension of an OCSP Response encodes its
+// critical field as the DEFAULT FALSE, as DER requires DEFAULT values to be omitted
+// Source: ITU-T X.690 Section 11.5
+func LintExtensionCriticalEncoding(ctx *LintContext) (LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	// copied so that appending does not write into the parsed response extensions
+	exts := append([]asn1.RawValue{}, basicResp.TBSResponseData.Extensions...)
+	for _, singleResp := range basicResp.TBSResponseData.Responses {
+		exts = append(exts, singleResp.Extensions...)
+	}
+
+	for _, ext := range exts {
+		elems, err := sequenceElements(ext.Bytes)
+		if err != nil {
+			return Error, fmt.Sprintf("Could not parse OCSP Response extension: %s", err.Error())
+		}
+
+		if len(elems) == 3 && elems[1].Class == asn1.ClassUniversal && elems[1].Tag == asn1.TagBoolean &&
+			len(elems[1].Bytes) == 1 && elems[1].Bytes[0] == 0 {
+			// the extension is named by its raw extnID if that is not an OID, as the encoding is wrong either way
+			name := fmt.Sprintf("%X", elems[0].FullBytes)
+			var oid asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(elems[0].FullBytes, &oid); err == nil {
+				name = oid.String()
+			}
+			return Failed, fmt.Sprintf("OCSP Response extension %s encodes its DEFAULT critical value FALSE, which DER requires to be omitted", name)
+		}
+	}
+
+	return Passed, "OCSP Response extensions do not encode their DEFAULT critical value"
+}
@@ -0,0 +1,185 @@
+package linter
+
+import (
+	"encoding/asn1"
+	"strings"
+	"testing"
+)
+
+// newRawLintContext returns a lint context for the given DER encoded response
+func newRawLintContext(rawResp []byte) *LintContext {
+	ctx := newLintContext(nil, nil)
+	ctx.RawResp = rawResp
+	ctx.ASN1, _ = ParseASN1Response(rawResp)
+	return ctx
+}
+
+// testExtension returns an encoded extension with the given critical field, omitted if nil
+func testExtension(critical []byte) []byte {
+	oid := derMarshal(asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2})
+	value := derMarshal([]byte{0x04, 0x01, 0x01})
+	if critical == nil {
+		return derSeq(oid, value)
+	}
+
+	return derSeq(oid, critical, value)
+}
+
+// TestLintResponseType tests LintResponseType, which checks that an OCSP Response
+// has the response type id-pkix-ocsp-basic
+// Source: RFC 6960 Section 4.2.1
+func TestLintResponseType(t *testing.T) {
+	t.Run("No raw response", func(t *testing.T) {
+		status, info := LintResponseType(newLintContext(nil, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should not have been applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Unparseable response", func(t *testing.T) {
+		status, info := LintResponseType(newRawLintContext([]byte{0x30, 0x01}))
+		if status != Error {
+			t.Errorf("Lint should have errored, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not basic response type", func(t *testing.T) {
+		status, info := LintResponseType(newRawLintContext(testASN1Resp{ResponseType: asn1.ObjectIdentifier{1, 2, 3}}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponseType(newRawLintContext(testASN1Resp{}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponseVersion tests LintResponseVersion, which checks that an OCSP Response has version v1
+// Source: RFC 6960 Section 4.2.1
+func TestLintResponseVersion(t *testing.T) {
+	t.Run("Version v2", func(t *testing.T) {
+		status, info := LintResponseVersion(newRawLintContext(testASN1Resp{Version: derExplicit(0, derMarshal(1))}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Encoded version v1", func(t *testing.T) {
+		status, info := LintResponseVersion(newRawLintContext(testASN1Resp{Version: derExplicit(0, derMarshal(0))}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponseVersion(newRawLintContext(testASN1Resp{}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintDefaultVersionEncoded tests LintDefaultVersionEncoded, which checks that an
+// OCSP Response does not encode its DEFAULT version
+// Source: ITU-T X.690 Section 11.5
+func TestLintDefaultVersionEncoded(t *testing.T) {
+	t.Run("Encoded version v1", func(t *testing.T) {
+		status, info := LintDefaultVersionEncoded(newRawLintContext(testASN1Resp{Version: derExplicit(0, derMarshal(0))}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintDefaultVersionEncoded(newRawLintContext(testASN1Resp{}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintUnexpectedFields tests LintUnexpectedFields, which checks that an
+// OCSP Response has no fields other than those defined
+// Source: RFC 6960 Section 4.2.1
+func TestLintUnexpectedFields(t *testing.T) {
+	t.Run("Unexpected field in ResponseData", func(t *testing.T) {
+		status, info := LintUnexpectedFields(newRawLintContext(testASN1Resp{ExtraTBSField: derMarshal(1)}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintUnexpectedFields(newRawLintContext(testASN1Resp{
+			Version:      derExplicit(0, derMarshal(0)),
+			SingleExts:   [][]byte{testExtension(nil)},
+			ResponseExts: [][]byte{testExtension(nil)},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintGeneralizedTimeEncoding tests LintGeneralizedTimeEncoding, which checks that
+// the GeneralizedTimes of an OCSP Response are in canonical DER form
+// Source: ITU-T X.690 Section 11.7
+func TestLintGeneralizedTimeEncoding(t *testing.T) {
+	badTimes := []string{"20200101000000+0100", "202001010000Z", "20200101000000.50Z", "20200101000000.Z"}
+	for _, badTime := range badTimes {
+		t.Run(badTime, func(t *testing.T) {
+			status, info := LintGeneralizedTimeEncoding(newRawLintContext(testASN1Resp{ProducedAt: badTime}.der()))
+			if status != Failed {
+				t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintGeneralizedTimeEncoding(newRawLintContext(testASN1Resp{ProducedAt: "20200101000000.5Z"}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintExtensionCriticalEncoding tests LintExtensionCriticalEncoding, which checks that
+// the extensions of an OCSP Response do not encode their DEFAULT critical value
+// Source: ITU-T X.690 Section 11.5
+func TestLintExtensionCriticalEncoding(t *testing.T) {
+	t.Run("Encoded critical FALSE in single extension", func(t *testing.T) {
+		status, info := LintExtensionCriticalEncoding(newRawLintContext(testASN1Resp{SingleExts: [][]byte{testExtension(derMarshal(false))}}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Encoded critical FALSE in response extension", func(t *testing.T) {
+		status, info := LintExtensionCriticalEncoding(newRawLintContext(testASN1Resp{ResponseExts: [][]byte{testExtension(derMarshal(false))}}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Encoded critical FALSE in extension without OID", func(t *testing.T) {
+		ext := derSeq(derMarshal(1), derMarshal(false), derMarshal([]byte{1}))
+		status, info := LintExtensionCriticalEncoding(newRawLintContext(testASN1Resp{ResponseExts: [][]byte{ext}}.der()))
+		if status != Failed || !strings.Contains(info, "extension 020101 ") {
+			t.Errorf("Lint should have failed naming the extension by its bytes, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintExtensionCriticalEncoding(newRawLintContext(testASN1Resp{
+			SingleExts:   [][]byte{testExtension(nil)},
+			ResponseExts: [][]byte{testExtension(derMarshal(true))},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
@@ -0,0 +1,332 @@
+package linter
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// OIDs of the OCSP extensions (RFC 6960 section 4.4)
+var (
+	OIDPKIXOCSPNonce          = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+	OIDPKIXOCSPCRL            = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 3}
+	OIDPKIXOCSPArchiveCutoff  = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 6}
+	OIDPKIXOCSPExtendedRevoke = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 9}
+)
+
+// The allowed lengths of the value of the nonce extension (RFC 8954 section 2.1)
+const (
+	NonceMinLength = 1
+	NonceMaxLength = 32
+)
+
+// KnownResponseExtensions maps the OIDs of the extensions that may be in the responseExtensions of an OCSP response to their names
+var KnownResponseExtensions = map[string]string{
+	OIDPKIXOCSPNonce.String():          "nonce",
+	OIDPKIXOCSPExtendedRevoke.String(): "extended revoke",
+}
+
+// KnownSingleExtensions maps the OIDs of the extensions that may be in the singleExtensions of an OCSP response to
+// their names, which are the OCSP single extensions and the CRL entry extensions (RFC 6960 section 4.4.5)
+var KnownSingleExtensions = map[string]string{
+	OIDPKIXOCSPCRL.String():           "CRL references",
+	OIDPKIXOCSPArchiveCutoff.String(): "archive cutoff",
+	"2.5.29.21":                       "reason code",
+	"2.5.29.24":                       "invalidity date",
+	"2.5.29.29":                       "certificate issuer",
+	"1.3.6.1.4.1.11129.2.4.5":         "signed certificate timestamp list", // RFC 6962 section 3.3
+}
+
+// CachingBreakingExtensions maps the OIDs of the response extensions that tie an OCSP response to
+// the request it answers, so that it cannot be pre-produced and cached, to their names
+var CachingBreakingExtensions = map[string]string{
+	OIDPKIXOCSPNonce.String(): "nonce",
+}
+
+// crlIDSpec defines the expected elements of the CrlID SEQUENCE, see RFC 6960 section 4.4.2
+var crlIDSpec = []elemSpec{
+	{asn1.ClassContextSpecific, []int{0}, true, "crlUrl"},
+	{asn1.ClassContextSpecific, []int{1}, true, "crlNum"},
+	{asn1.ClassContextSpecific, []int{2}, true, "crlTime"},
+}
+
+// crlIDFieldTags maps the context specific tags of the fields of CrlID to the universal tags of their explicitly tagged values
+var crlIDFieldTags = map[int]int{
+	0: asn1.TagIA5String,
+	1: asn1.TagInteger,
+	2: TagGeneralizedTime,
+}
+
+// isValidCrlIDField returns whether a field of a CrlID, whose tag is known to be one of crlIDFieldTags,
+// explicitly tags a single value of the type of the field
+func isValidCrlIDField(field asn1.RawValue) bool {
+	var value asn1.RawValue
+	rest, err := asn1.Unmarshal(field.Bytes, &value)
+	if err != nil || len(rest) > 0 || value.Class != asn1.ClassUniversal || value.Tag != crlIDFieldTags[field.Tag] {
+		return false
+	}
+
+	switch value.Tag {
+	case asn1.TagInteger:
+		var num *big.Int
+		_, err = asn1.Unmarshal(value.FullBytes, &num)
+	case TagGeneralizedTime:
+		var t time.Time
+		_, err = asn1.UnmarshalWithParams(value.FullBytes, &t, "generalized")
+	}
+
+	return err == nil
+}
+
+// parseExtensions parses the raw extensions of the ASN.1 structure of an OCSP response
+func parseExtensions(raws []asn1.RawValue) ([]pkix.Extension, error) {
+	exts := []pkix.Extension{}
+	for _, raw := range raws {
+		var ext pkix.Extension
+		rest, err := asn1.Unmarshal(raw.FullBytes, &ext)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing extension: %w", err)
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("Trailing data after extension")
+		}
+
+		exts = append(exts, ext)
+	}
+
+	return exts, nil
+}
+
+// getResponseExtensions returns the responseExtensions of the OCSP response being linted,
+// or nil and the status and info the lint should return if they are not available
+func getResponseExtensions(ctx *LintContext) ([]pkix.Extension, LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return nil, status, info
+	}
+
+	exts, err := parseExtensions(basicResp.TBSResponseData.Extensions)
+	if err != nil {
+		return nil, Error, fmt.Sprintf("Could not parse OCSP Response extensions: %s", err.Error())
+	}
+
+	return exts, "", ""
+}
+
+// getSingleExtensions returns the singleExtensions of the SingleResponse being linted,
+// or nil and the status and info the lint should return if they are not available
+func getSingleExtensions(ctx *LintContext) ([]pkix.Extension, LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return nil, status, info
+	}
+
+	single := getSingleResponse(ctx)
+	if single == nil {
+		return nil, NotApplicable, "OCSP Response has no single responses"
+	}
+
+	exts, err := parseExtensions(single.Extensions)
+	if err != nil {
+		return nil, Error, fmt.Sprintf("Could not parse OCSP Response single extensions: %s", err.Error())
+	}
+
+	return exts, "", ""
+}
+
+// findExtension returns the extension with the given OID out of exts, or nil if there is none
+func findExtension(exts []pkix.Extension, oid asn1.ObjectIdentifier) *pkix.Extension {
+	for idx := range exts {
+		if exts[idx].Id.Equal(oid) {
+			return &exts[idx]
+		}
+	}
+
+	return nil
+}
+
+// unknownCriticalExtensions returns the OIDs of the critical extensions out of exts that are not in known
+func unknownCriticalExtensions(exts []pkix.Extension, known map[string]string) []string {
+	var unknown []string
+	for _, ext := range exts {
+		if _, ok := known[ext.Id.String()]; ext.Critical && !ok {
+			unknown = append(unknown, ext.Id.String())
+		}
+	}
+
+	return unknown
+}
+
+// LintResponseExtensionCriticalUnknown checks that an OCSP Response has no critical responseExtensions
+// that are not defined for OCSP responses, as clients have to reject responses with critical
+// extensions they do not recognize
+// Source: RFC 6960 Section 4.4
+func LintResponseExtensionCriticalUnknown(ctx *LintContext) (LintStatus, string) {
+	exts, status, info := getResponseExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	if unknown := unknownCriticalExtensions(exts, KnownResponseExtensions); len(unknown) > 0 {
+		return Failed, fmt.Sprintf("OCSP Response has unrecognized critical response extensions %s", strings.Join(unknown, ", "))
+	}
+
+	return Passed, "OCSP Response has no unrecognized critical response extensions"
+}
+
+// LintSingleExtensionCriticalUnknown checks that the SingleResponse of an OCSP Response has no critical
+// singleExtensions that are not defined for OCSP responses, as clients have to reject responses with
+// critical extensions they do not recognize
+// Source: RFC 6960 Section 4.4
+func LintSingleExtensionCriticalUnknown(ctx *LintContext) (LintStatus, string) {
+	exts, status, info := getSingleExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	if unknown := unknownCriticalExtensions(exts, KnownSingleExtensions); len(unknown) > 0 {
+		return Failed, fmt.Sprintf("OCSP Response has unrecognized critical single extensions %s", strings.Join(unknown, ", "))
+	}
+
+	return Passed, "OCSP Response has no unrecognized critical single extensions"
+}
+
+// LintNonce checks that the nonce extension of an OCSP Response, if present, is an OCTET STRING of 1 to 32 bytes
+// Source: RFC 8954 Section 2.1
+func LintNonce(ctx *LintContext) (LintStatus, string) {
+	exts, status, info := getResponseExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	ext := findExtension(exts, OIDPKIXOCSPNonce)
+	if ext == nil {
+		return NotApplicable, "OCSP Response has no nonce extension"
+	}
+
+	var nonce []byte
+	rest, err := asn1.Unmarshal(ext.Value, &nonce)
+	if err != nil || len(rest) > 0 {
+		return Failed, fmt.Sprintf("OCSP Response nonce extension value %X is not an OCTET STRING", ext.Value)
+	}
+
+	if len(nonce) < NonceMinLength || len(nonce) > NonceMaxLength {
+		return Failed, fmt.Sprintf("OCSP Response nonce is %d bytes long instead of %d to %d bytes",
+			len(nonce), NonceMinLength, NonceMaxLength)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response nonce is %d bytes long", len(nonce))
+}
+
+// LintArchiveCutoff checks that the archive cutoff extension of an OCSP Response, if present, is a GeneralizedTime
+// Source: RFC 6960 Section 4.4.4
+func LintArchiveCutoff(ctx *LintContext) (LintStatus, string) {
+	exts, status, info := getSingleExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	ext := findExtension(exts, OIDPKIXOCSPArchiveCutoff)
+	if ext == nil {
+		return NotApplicable, "OCSP Response has no archive cutoff extension"
+	}
+
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(ext.Value, &raw)
+	if err != nil || len(rest) > 0 || raw.Class != asn1.ClassUniversal || raw.Tag != TagGeneralizedTime {
+		return Failed, fmt.Sprintf("OCSP Response archive cutoff extension value %X is not a GeneralizedTime", ext.Value)
+	}
+
+	var cutoff time.Time
+	_, err = asn1.UnmarshalWithParams(ext.Value, &cutoff, "generalized")
+	if err != nil || !isCanonicalGeneralizedTime(string(raw.Bytes)) {
+		return Failed, fmt.Sprintf("OCSP Response archive cutoff %q is not a valid GeneralizedTime", raw.Bytes)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response archive cutoff %s is a valid GeneralizedTime", cutoff)
+}
+
+// LintCRLReferences checks that the CRL references extension of an OCSP Response, if present, is a
+// CrlID made of an optional IA5String crlUrl, INTEGER crlNum and GeneralizedTime crlTime
+// Source: RFC 6960 Section 4.4.2
+func LintCRLReferences(ctx *LintContext) (LintStatus, string) {
+	exts, status, info := getSingleExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	ext := findExtension(exts, OIDPKIXOCSPCRL)
+	if ext == nil {
+		return NotApplicable, "OCSP Response has no CRL references extension"
+	}
+
+	unexpected, err := unexpectedElements(ext.Value, crlIDSpec)
+	if err != nil {
+		return Failed, fmt.Sprintf("OCSP Response CRL references extension value is not a CrlID: %s", err.Error())
+	}
+
+	if unexpected > 0 {
+		return Failed, fmt.Sprintf("OCSP Response CRL references extension value has %d unexpected fields", unexpected)
+	}
+
+	var seq asn1.RawValue
+	rest, _ := asn1.Unmarshal(ext.Value, &seq)
+	if len(rest) > 0 {
+		return Failed, "OCSP Response CRL references extension value has trailing data after its CrlID"
+	}
+
+	fields, err := sequenceElements(seq.Bytes)
+	if err != nil {
+		return Failed, fmt.Sprintf("OCSP Response CRL references extension value is not a valid CrlID: %s", err.Error())
+	}
+
+	for _, field := range fields {
+		if !isValidCrlIDField(field) {
+			return Failed, fmt.Sprintf("OCSP Response CRL references extension value has an invalid %s",
+				crlIDSpec[field.Tag].name)
+		}
+	}
+
+	return Passed, "OCSP Response CRL references extension value is a valid CrlID"
+}
+
+// LintResponseExtensionCaching checks that an OCSP Response has no extensions that tie it to the request
+// it answers, such as a nonce, as they prevent responses from being pre-produced and cached
+// A nonce is not checked if the OCSP request had one, LintNonceCaching checks that it is echoed consistently instead
+// Source: RFC 5019 Section 2.1
+func LintResponseExtensionCaching(ctx *LintContext) (LintStatus, string) {
+	exts, status, info := getResponseExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	requestedNonce := false
+	if ctx.RawRequest != nil {
+		reqNonce, err := getRequestNonce(ctx)
+		if err != nil {
+			return Error, fmt.Sprintf("Could not get the nonce of the OCSP request: %s", err.Error())
+		}
+		requestedNonce = reqNonce != nil
+	}
+
+	var breaking []string
+	for _, ext := range exts {
+		if requestedNonce && ext.Id.Equal(OIDPKIXOCSPNonce) {
+			continue
+		}
+
+		if name, ok := CachingBreakingExtensions[ext.Id.String()]; ok {
+			breaking = append(breaking, name)
+		}
+	}
+
+	if len(breaking) > 0 {
+		return Warn, fmt.Sprintf("OCSP Response has %s extensions, which prevent it from being pre-produced and cached",
+			strings.Join(breaking, ", "))
+	}
+
+	return Passed, "OCSP Response has no extensions that prevent it from being pre-produced and cached"
+}
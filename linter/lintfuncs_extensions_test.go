@@ -0,0 +1,219 @@
+package linter
+
+import (
+	"encoding/asn1"
+	"testing"
+)
+
+// newTestExtension returns an encoded extension with the given OID, criticality and DER encoded value
+func newTestExtension(oid asn1.ObjectIdentifier, critical bool, value []byte) []byte {
+	if !critical {
+		return derSeq(derMarshal(oid), derMarshal(value))
+	}
+
+	return derSeq(derMarshal(oid), derMarshal(true), derMarshal(value))
+}
+
+// generalizedTime returns the DER encoding of the GeneralizedTime t
+func generalizedTime(t string) []byte {
+	return derElem(asn1.ClassUniversal, TagGeneralizedTime, false, []byte(t))
+}
+
+// TestLintResponseExtensionCriticalUnknown tests LintResponseExtensionCriticalUnknown, which checks
+// that an OCSP Response has no unrecognized critical response extensions
+// Source: RFC 6960 Section 4.4
+func TestLintResponseExtensionCriticalUnknown(t *testing.T) {
+	unknownOID := asn1.ObjectIdentifier{1, 2, 3, 4}
+
+	t.Run("Unknown critical extension", func(t *testing.T) {
+		status, info := LintResponseExtensionCriticalUnknown(newRawLintContext(testASN1Resp{
+			ResponseExts: [][]byte{newTestExtension(unknownOID, true, derMarshal(asn1.NullRawValue))},
+		}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Unknown non critical extension", func(t *testing.T) {
+		status, info := LintResponseExtensionCriticalUnknown(newRawLintContext(testASN1Resp{
+			ResponseExts: [][]byte{newTestExtension(unknownOID, false, derMarshal(asn1.NullRawValue))},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Known critical extension", func(t *testing.T) {
+		status, info := LintResponseExtensionCriticalUnknown(newRawLintContext(testASN1Resp{
+			ResponseExts: [][]byte{newTestExtension(OIDPKIXOCSPNonce, true, derMarshal([]byte{1}))},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not available", func(t *testing.T) {
+		status, info := LintResponseExtensionCriticalUnknown(newLintContext(nil, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintSingleExtensionCriticalUnknown tests LintSingleExtensionCriticalUnknown, which checks
+// that an OCSP Response has no unrecognized critical single extensions
+// Source: RFC 6960 Section 4.4
+func TestLintSingleExtensionCriticalUnknown(t *testing.T) {
+	t.Run("Unknown critical extension", func(t *testing.T) {
+		status, info := LintSingleExtensionCriticalUnknown(newRawLintContext(testASN1Resp{
+			SingleExts: [][]byte{newTestExtension(asn1.ObjectIdentifier{1, 2, 3, 4}, true, derMarshal(asn1.NullRawValue))},
+		}.der()))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Known critical extension", func(t *testing.T) {
+		status, info := LintSingleExtensionCriticalUnknown(newRawLintContext(testASN1Resp{
+			SingleExts: [][]byte{newTestExtension(OIDPKIXOCSPArchiveCutoff, true, generalizedTime("20200101000000Z"))},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Response extension", func(t *testing.T) {
+		status, info := LintSingleExtensionCriticalUnknown(newRawLintContext(testASN1Resp{
+			ResponseExts: [][]byte{newTestExtension(asn1.ObjectIdentifier{1, 2, 3, 4}, true, derMarshal(asn1.NullRawValue))},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintNonce tests LintNonce, which checks that the nonce of an OCSP Response is an OCTET STRING of 1 to 32 bytes
+// Source: RFC 8954 Section 2.1
+func TestLintNonce(t *testing.T) {
+	tests := map[string]struct {
+		value    []byte
+		expected LintStatus
+	}{
+		"Happy path":             {derMarshal(make([]byte, 32)), Passed},
+		"Empty nonce":            {derMarshal([]byte{}), Failed},
+		"Nonce too long":         {derMarshal(make([]byte, 33)), Failed},
+		"Nonce not OCTET STRING": {make([]byte, 16), Failed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, info := LintNonce(newRawLintContext(testASN1Resp{
+				ResponseExts: [][]byte{newTestExtension(OIDPKIXOCSPNonce, false, test.value)},
+			}.der()))
+			if status != test.expected {
+				t.Errorf("Lint should have had status %s, instead got status %s: %s", test.expected, status, info)
+			}
+		})
+	}
+
+	t.Run("No nonce", func(t *testing.T) {
+		status, info := LintNonce(newRawLintContext(testASN1Resp{}.der()))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintArchiveCutoff tests LintArchiveCutoff, which checks that the archive cutoff of an OCSP Response is a GeneralizedTime
+// Source: RFC 6960 Section 4.4.4
+func TestLintArchiveCutoff(t *testing.T) {
+	tests := map[string]struct {
+		value    []byte
+		expected LintStatus
+	}{
+		"Happy path":                   {generalizedTime("20200101000000Z"), Passed},
+		"UTCTime":                      {derElem(asn1.ClassUniversal, asn1.TagUTCTime, false, []byte("200101000000Z")), Failed},
+		"Non canonical time":           {generalizedTime("20200101000000.0Z"), Failed},
+		"Trailing data after the time": {append(generalizedTime("20200101000000Z"), 0), Failed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, info := LintArchiveCutoff(newRawLintContext(testASN1Resp{
+				SingleExts: [][]byte{newTestExtension(OIDPKIXOCSPArchiveCutoff, false, test.value)},
+			}.der()))
+			if status != test.expected {
+				t.Errorf("Lint should have had status %s, instead got status %s: %s", test.expected, status, info)
+			}
+		})
+	}
+
+	t.Run("No archive cutoff", func(t *testing.T) {
+		status, info := LintArchiveCutoff(newRawLintContext(testASN1Resp{}.der()))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintCRLReferences tests LintCRLReferences, which checks that the CRL references of an OCSP Response are a valid CrlID
+// Source: RFC 6960 Section 4.4.2
+func TestLintCRLReferences(t *testing.T) {
+	crlURL := derExplicit(0, derElem(asn1.ClassUniversal, asn1.TagIA5String, false, []byte("http://crl.example.com")))
+	crlNum := derExplicit(1, derMarshal(42))
+	crlTime := derExplicit(2, generalizedTime("20200101000000Z"))
+
+	tests := map[string]struct {
+		value    []byte
+		expected LintStatus
+	}{
+		"Happy path":          {derSeq(crlURL, crlNum, crlTime), Passed},
+		"Only crlNum":         {derSeq(crlNum), Passed},
+		"Fields out of order": {derSeq(crlNum, crlURL), Failed},
+		"Unexpected field":    {derSeq(crlURL, derMarshal(1)), Failed},
+		"crlNum not INTEGER":  {derSeq(derExplicit(1, derMarshal("42"))), Failed},
+		"Not a SEQUENCE":      {derMarshal(42), Failed},
+		"Trailing data":       {append(derSeq(crlNum), 0), Failed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, info := LintCRLReferences(newRawLintContext(testASN1Resp{
+				SingleExts: [][]byte{newTestExtension(OIDPKIXOCSPCRL, false, test.value)},
+			}.der()))
+			if status != test.expected {
+				t.Errorf("Lint should have had status %s, instead got status %s: %s", test.expected, status, info)
+			}
+		})
+	}
+}
+
+// TestLintResponseExtensionCaching tests LintResponseExtensionCaching, which checks that an
+// OCSP Response has no extensions that prevent it from being cached
+// Source: RFC 5019 Section 2.1
+func TestLintResponseExtensionCaching(t *testing.T) {
+	t.Run("Nonce", func(t *testing.T) {
+		status, info := LintResponseExtensionCaching(newRawLintContext(testASN1Resp{
+			ResponseExts: [][]byte{newTestExtension(OIDPKIXOCSPNonce, false, derMarshal([]byte{1}))},
+		}.der()))
+		if status != Warn {
+			t.Errorf("Lint should have warned, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Nonce echoed for request with nonce", func(t *testing.T) {
+		status, info := LintResponseExtensionCaching(newNonceLintContext(echoNonce, "no-cache"))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponseExtensionCaching(newRawLintContext(testASN1Resp{
+			ResponseExts: [][]byte{newTestExtension(OIDPKIXOCSPExtendedRevoke, false, derMarshal(asn1.NullRawValue))},
+		}.der()))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package linter
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// rawOCSPRequest mirrors the parts of OCSPRequest needed to get its requestExtensions (RFC 6960 section 4.1.1)
+type rawOCSPRequest struct {
+	TBSRequest struct {
+		Version           int           `asn1:"explicit,tag:0,default:0,optional"`
+		RequestorName     asn1.RawValue `asn1:"explicit,tag:1,optional"`
+		RequestList       []asn1.RawValue
+		RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+	}
+}
+
+// getRequestNonce returns the value of the nonce extension of the OCSP request that was sent, nil if it has none
+func getRequestNonce(ctx *LintContext) ([]byte, error) {
+	var req rawOCSPRequest
+	_, err := asn1.Unmarshal(ctx.RawRequest, &req)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OCSP request: %w", err)
+	}
+
+	ext := findExtension(req.TBSRequest.RequestExtensions, OIDPKIXOCSPNonce)
+	if ext == nil {
+		return nil, nil
+	}
+
+	return ext.Value, nil
+}
+
+// isDeclaredCacheable returns whether the Cache-Control header of an http response allows caching it,
+// which is the case if it has a positive max-age or s-maxage, or is public, and is not no-store, no-cache or private
+func isDeclaredCacheable(header http.Header) bool {
+	cacheable := false
+	for _, directive := range strings.Split(strings.ToLower(header.Get("Cache-Control")), ",") {
+		name, value := strings.TrimSpace(directive), ""
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name, value = strings.TrimSpace(name[:idx]), strings.Trim(strings.TrimSpace(name[idx+1:]), `"`)
+		}
+
+		switch name {
+		case "no-store", "no-cache", "private":
+			return false
+		case "max-age", "s-maxage":
+			if age, err := strconv.Atoi(value); err == nil && age > 0 {
+				cacheable = true
+			}
+		case "public":
+			cacheable = true
+		}
+	}
+
+	return cacheable
+}
+
+// LintCertIDMatchesRequest checks that the CertID of an OCSP Response, including its hash algorithm,
+// identifies the same certificate as the CertID of the OCSP request that was sent
+// Source: RFC 6960 Section 3.2
+func LintCertIDMatchesRequest(ctx *LintContext) (LintStatus, string) {
+	req := ctx.Request
+	if req == nil {
+		return NotApplicable, "OCSP Response was not fetched with an OCSP request"
+	}
+
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	single := getSingleResponse(ctx)
+	if single == nil {
+		return Failed, "OCSP Response has no single responses"
+	}
+
+	certID := single.CertID
+
+	var mismatches []string
+	if ctx.Resp.IssuerHash != req.HashAlgorithm {
+		mismatches = append(mismatches, fmt.Sprintf("hash algorithm %s instead of %s", ctx.Resp.IssuerHash, req.HashAlgorithm))
+	}
+
+	if !bytes.Equal(certID.IssuerNameHash, req.IssuerNameHash) {
+		mismatches = append(mismatches, fmt.Sprintf("issuer name hash %X instead of %X", certID.IssuerNameHash, req.IssuerNameHash))
+	}
+
+	if !bytes.Equal(certID.IssuerKeyHash, req.IssuerKeyHash) {
+		mismatches = append(mismatches, fmt.Sprintf("issuer key hash %X instead of %X", certID.IssuerKeyHash, req.IssuerKeyHash))
+	}
+
+	if certID.SerialNumber == nil || certID.SerialNumber.Cmp(req.SerialNumber) != 0 {
+		mismatches = append(mismatches, fmt.Sprintf("serial number %s instead of %s", certID.SerialNumber, req.SerialNumber))
+	}
+
+	if len(mismatches) > 0 {
+		return Failed, fmt.Sprintf("OCSP Response CertID does not match the OCSP request, it has %s", strings.Join(mismatches, ", "))
+	}
+
+	return Passed, "OCSP Response CertID matches the OCSP request"
+}
+
+// LintSingleResponseNotRequested checks that every SingleResponse of an OCSP Response is for the certificate
+// it was requested for, which is the one in the OCSP request that was sent or the certificate it was stapled for
+// Source: RFC 6960 Section 4.2.2.3
+func LintSingleResponseNotRequested(ctx *LintContext) (LintStatus, string) {
+	var requested string
+	var isRequested func(certID RawCertID) bool
+	switch {
+	case ctx.Request != nil:
+		req := ctx.Request
+		requested = "OCSP request"
+		isRequested = func(certID RawCertID) bool {
+			return bytes.Equal(certID.IssuerNameHash, req.IssuerNameHash) &&
+				bytes.Equal(certID.IssuerKeyHash, req.IssuerKeyHash) &&
+				certID.SerialNumber != nil && certID.SerialNumber.Cmp(req.SerialNumber) == 0
+		}
+	case ctx.LeafCert != nil:
+		leaf := ctx.LeafCert
+		requested = "certificate"
+		isRequested = func(certID RawCertID) bool {
+			return certID.SerialNumber != nil && certID.SerialNumber.Cmp(leaf.SerialNumber) == 0
+		}
+	default:
+		return NotApplicable, "OCSP Response was not obtained for a known certificate"
+	}
+
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	var unrequested []string
+	for _, single := range basicResp.TBSResponseData.Responses {
+		if !isRequested(single.CertID) {
+			unrequested = append(unrequested, fmt.Sprintf("%s", single.CertID.SerialNumber))
+		}
+	}
+
+	if len(unrequested) > 0 {
+		return Warn, fmt.Sprintf("OCSP Response has SingleResponses that do not match the %s, for serial numbers %s",
+			requested, strings.Join(unrequested, ", "))
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response only has SingleResponses that match the %s", requested)
+}
+
+// LintNonceMatchesRequest checks that an OCSP Response fetched with an OCSP request carrying a nonce
+// either echoes that exact nonce or has none, as a different nonce means the response is not bound to the request
+// Source: RFC 6960 Section 4.4.1
+func LintNonceMatchesRequest(ctx *LintContext) (LintStatus, string) {
+	if ctx.RawRequest == nil {
+		return NotApplicable, "OCSP Response was not fetched with an OCSP request"
+	}
+
+	reqNonce, err := getRequestNonce(ctx)
+	if err != nil {
+		return Error, fmt.Sprintf("Could not get the nonce of the OCSP request: %s", err.Error())
+	}
+
+	if reqNonce == nil {
+		return NotApplicable, "OCSP request had no nonce"
+	}
+
+	exts, status, info := getResponseExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	ext := findExtension(exts, OIDPKIXOCSPNonce)
+	if ext == nil {
+		return Passed, "OCSP Response omits the nonce of the OCSP request"
+	}
+
+	if !bytes.Equal(ext.Value, reqNonce) {
+		return Failed, fmt.Sprintf("OCSP Response nonce %X does not match the nonce %X of the OCSP request", ext.Value, reqNonce)
+	}
+
+	return Passed, "OCSP Response echoes the nonce of the OCSP request"
+}
+
+// LintNonceCaching checks that an OCSP responder that was sent a nonce either echoes it in a response whose
+// Cache-Control header does not allow caching it, or omits it from a pre-produced response whose Cache-Control
+// header allows caching it, as a response echoing a nonce only answers that one request
+// Source: RFC 5019 Section 6.2
+func LintNonceCaching(ctx *LintContext) (LintStatus, string) {
+	if ctx.RawRequest == nil || ctx.HTTP == nil {
+		return NotApplicable, "OCSP Response was not fetched with an OCSP request"
+	}
+
+	reqNonce, err := getRequestNonce(ctx)
+	if err != nil {
+		return Error, fmt.Sprintf("Could not get the nonce of the OCSP request: %s", err.Error())
+	}
+
+	if reqNonce == nil {
+		return NotApplicable, "OCSP request had no nonce"
+	}
+
+	exts, status, info := getResponseExtensions(ctx)
+	if exts == nil {
+		return status, info
+	}
+
+	echoed := findExtension(exts, OIDPKIXOCSPNonce) != nil
+	cacheControl := ctx.HTTP.Header.Get("Cache-Control")
+	cacheable := isDeclaredCacheable(ctx.HTTP.Header)
+
+	if echoed && cacheable {
+		return Warn, fmt.Sprintf("OCSP Response echoes the nonce of the OCSP request but its Cache-Control header %q allows caching it",
+			cacheControl)
+	}
+
+	if !echoed && !cacheable {
+		return Warn, fmt.Sprintf("OCSP Response omits the nonce of the OCSP request but its Cache-Control header %q does not allow caching it",
+			cacheControl)
+	}
+
+	if echoed {
+		return Passed, fmt.Sprintf("OCSP Response echoes the nonce of the OCSP request and its Cache-Control header %q does not allow caching it",
+			cacheControl)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response omits the nonce of the OCSP request and its Cache-Control header %q allows caching it",
+		cacheControl)
+}
@@ -0,0 +1,266 @@
+package linter
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newFetchedLintContext returns a lint context of a good OCSP response for leafCert signed by
+// issuer, fetched with an OCSP request for reqCert using the reqHash hash algorithm
+func newFetchedLintContext(leafCert *x509.Certificate, reqCert *x509.Certificate, reqHash crypto.Hash,
+	issuer *x509.Certificate, issuerKey crypto.Signer) *LintContext {
+	rawReq, err := ocsp.CreateRequest(reqCert, issuer, &ocsp.RequestOptions{Hash: reqHash})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	req, err := ocsp.ParseRequest(rawReq)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	ctx := newCreatedLintContext(ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: leafCert.SerialNumber,
+		ThisUpdate:   time.Now(),
+		NextUpdate:   time.Now().Add(24 * time.Hour),
+	}, issuer, issuerKey)
+	ctx.LeafCert = leafCert
+	ctx.Request = req
+	ctx.RawRequest = rawReq
+	ctx.Transport = TransportFetched
+	return ctx
+}
+
+// newTestLeaf creates a subscriber certificate with the given serial number issued by issuer
+func newTestLeaf(serial int64, issuer *x509.Certificate, issuerKey crypto.Signer) *x509.Certificate {
+	cert, _ := newTestCert(&x509.Certificate{
+		Subject:      pkix.Name{CommonName: "Test Subscriber"},
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}, issuer, issuerKey)
+	return cert
+}
+
+// TestLintCertIDMatchesRequest tests LintCertIDMatchesRequest, which checks that the
+// CertID of an OCSP Response matches the CertID of the OCSP request
+// Source: RFC 6960 Section 3.2
+func TestLintCertIDMatchesRequest(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+	leafCert := newTestLeaf(100, issuer, issuerKey)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintCertIDMatchesRequest(newFetchedLintContext(leafCert, leafCert, crypto.SHA1, issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not fetched", func(t *testing.T) {
+		ctx := newFetchedLintContext(leafCert, leafCert, crypto.SHA1, issuer, issuerKey)
+		ctx.Request = nil
+		status, info := LintCertIDMatchesRequest(ctx)
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	otherCert := newTestLeaf(200, issuer, issuerKey)
+	t.Run("Response for another certificate", func(t *testing.T) {
+		status, info := LintCertIDMatchesRequest(newFetchedLintContext(otherCert, leafCert, crypto.SHA1, issuer, issuerKey))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Response with another hash algorithm", func(t *testing.T) {
+		status, info := LintCertIDMatchesRequest(newFetchedLintContext(leafCert, leafCert, crypto.SHA256, issuer, issuerKey))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	otherIssuer, otherIssuerKey := newTestIssuer("Other CA")
+	t.Run("Response for another issuer", func(t *testing.T) {
+		ctx := newFetchedLintContext(leafCert, leafCert, crypto.SHA1, issuer, issuerKey)
+		ctx.Request = newFetchedLintContext(leafCert, leafCert, crypto.SHA1, otherIssuer, otherIssuerKey).Request
+		status, info := LintCertIDMatchesRequest(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintSingleResponseNotRequested tests LintSingleResponseNotRequested, which checks that
+// every SingleResponse of an OCSP Response is for the requested certificate
+// Source: RFC 6960 Section 4.2.2.3
+func TestLintSingleResponseNotRequested(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+	leafCert := newTestLeaf(100, issuer, issuerKey)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintSingleResponseNotRequested(newFetchedLintContext(leafCert, leafCert, crypto.SHA1, issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	otherCert := newTestLeaf(200, issuer, issuerKey)
+	t.Run("Response for another certificate", func(t *testing.T) {
+		status, info := LintSingleResponseNotRequested(newFetchedLintContext(otherCert, leafCert, crypto.SHA1, issuer, issuerKey))
+		if status != Warn {
+			t.Errorf("Lint should have warned, instead got status %s: %s", status, info)
+		}
+	})
+
+	multipleResp, rawResp, err := ocsptools.Tools{}.ReadOCSPResp(RespMultiple)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespMultiple, err))
+	}
+
+	ctx := newLintContext(multipleResp, &x509.Certificate{SerialNumber: big.NewInt(1)})
+	ctx.RawResp = rawResp
+	ctx.ASN1, _ = ParseASN1Response(rawResp)
+	t.Run("Response with an unrequested SingleResponse", func(t *testing.T) {
+		status, info := LintSingleResponseNotRequested(ctx)
+		if status != Warn {
+			t.Errorf("Lint should have warned, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No requested certificate", func(t *testing.T) {
+		ctx := *ctx
+		ctx.LeafCert = nil
+		status, info := LintSingleResponseNotRequested(&ctx)
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// newNonceLintContext returns a lint context of an OCSP response fetched with an OCSP request with a random nonce
+// and served with the given Cache-Control header, whose nonce extension value is respNonce of the request nonce,
+// omitted if nil
+func newNonceLintContext(respNonce func(reqNonce []byte) []byte, cacheControl string) *LintContext {
+	issuer, issuerKey := newTestIssuer("Test CA")
+	leafCert := newTestLeaf(100, issuer, issuerKey)
+
+	_, rawReq, err := helpers.Helpers{Nonce: true}.CreateOCSPReq("http://ocsp.example.com", leafCert, issuer, http.MethodGet, crypto.SHA1)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	ctx := newLintContext(nil, leafCert)
+	ctx.RawRequest = rawReq
+	reqNonce, err := getRequestNonce(ctx)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var exts [][]byte
+	if value := respNonce(reqNonce); value != nil {
+		exts = [][]byte{newTestExtension(OIDPKIXOCSPNonce, false, value)}
+	}
+
+	rawCtx := newRawLintContext(testASN1Resp{ResponseExts: exts}.der())
+	ctx.RawResp = rawCtx.RawResp
+	ctx.ASN1 = rawCtx.ASN1
+	ctx.HTTP = &HTTPMetadata{Header: http.Header{}}
+	if cacheControl != "" {
+		ctx.HTTP.Header.Set("Cache-Control", cacheControl)
+	}
+	return ctx
+}
+
+// echoNonce, otherNonce and omitNonce return the nonce an OCSP responder responds with for a request nonce
+var (
+	echoNonce  = func(reqNonce []byte) []byte { return reqNonce }
+	otherNonce = func(reqNonce []byte) []byte { return derMarshal([]byte{1, 2, 3}) }
+	omitNonce  = func(reqNonce []byte) []byte { return nil }
+)
+
+// TestLintNonceMatchesRequest tests LintNonceMatchesRequest, which checks that an OCSP Response
+// either echoes the nonce of the OCSP request or has none
+// Source: RFC 6960 Section 4.4.1
+func TestLintNonceMatchesRequest(t *testing.T) {
+	tests := map[string]struct {
+		respNonce func(reqNonce []byte) []byte
+		expected  LintStatus
+	}{
+		"Nonce echoed":    {echoNonce, Passed},
+		"Nonce omitted":   {omitNonce, Passed},
+		"Different nonce": {otherNonce, Failed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, info := LintNonceMatchesRequest(newNonceLintContext(test.respNonce, ""))
+			if status != test.expected {
+				t.Errorf("Lint should have had status %s, instead got status %s: %s", test.expected, status, info)
+			}
+		})
+	}
+
+	t.Run("Request without nonce", func(t *testing.T) {
+		issuer, issuerKey := newTestIssuer("Test CA")
+		leafCert := newTestLeaf(100, issuer, issuerKey)
+		status, info := LintNonceMatchesRequest(newFetchedLintContext(leafCert, leafCert, crypto.SHA1, issuer, issuerKey))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not fetched", func(t *testing.T) {
+		ctx := newNonceLintContext(otherNonce, "")
+		ctx.RawRequest = nil
+		status, info := LintNonceMatchesRequest(ctx)
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintNonceCaching tests LintNonceCaching, which checks that an OCSP Response echoes or
+// omits the nonce of the OCSP request consistently with its Cache-Control header
+// Source: RFC 5019 Section 6.2
+func TestLintNonceCaching(t *testing.T) {
+	tests := map[string]struct {
+		respNonce    func(reqNonce []byte) []byte
+		cacheControl string
+		expected     LintStatus
+	}{
+		"Nonce echoed in uncacheable response": {echoNonce, "no-cache", Passed},
+		"Nonce echoed in cacheable response":   {echoNonce, "max-age=3600, public", Warn},
+		"Nonce omitted in cacheable response":  {omitNonce, "public, max-age=3600, no-transform, must-revalidate", Passed},
+		"Nonce omitted without Cache-Control":  {omitNonce, "", Warn},
+		"Nonce omitted in private response":    {omitNonce, "max-age=3600, private", Warn},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, info := LintNonceCaching(newNonceLintContext(test.respNonce, test.cacheControl))
+			if status != test.expected {
+				t.Errorf("Lint should have had status %s, instead got status %s: %s", test.expected, status, info)
+			}
+		})
+	}
+
+	t.Run("No http response", func(t *testing.T) {
+		ctx := newNonceLintContext(echoNonce, "")
+		ctx.HTTP = nil
+		status, info := LintNonceCaching(ctx)
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
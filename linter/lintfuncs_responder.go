@@ -0,0 +1,240 @@
+package linter
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"time"
+)
+
+const (
+	ResponderCertLifetimeLimit = 8760 * time.Hour // 365 days
+)
+
+// OIDPKIXOCSPNoCheck is the OID of the id-pkix-ocsp-nocheck extension (RFC 6960 section 4.2.2.2.1)
+var OIDPKIXOCSPNoCheck = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+
+// getResponderCert returns the delegated responder certificate of the OCSP response being linted,
+// or nil if the response is signed by the issuer itself
+func getResponderCert(ctx *LintContext) *x509.Certificate {
+	cert := ctx.Resp.Certificate
+	if cert == nil {
+		return nil
+	}
+
+	// some responders embed the issuer certificate even though the issuer signs the response
+	if ctx.IssuerCert != nil && bytes.Equal(cert.Raw, ctx.IssuerCert.Raw) {
+		return nil
+	}
+
+	return cert
+}
+
+// getSigner returns the certificate whose key signed the OCSP response being linted, which is
+// the delegated responder certificate if there is one and otherwise the issuer certificate,
+// along with a description of it, or nil if neither is available
+func getSigner(ctx *LintContext) (*x509.Certificate, string) {
+	if cert := getResponderCert(ctx); cert != nil {
+		return cert, "delegated responder certificate"
+	}
+
+	if ctx.IssuerCert != nil {
+		return ctx.IssuerCert, "issuer certificate"
+	}
+
+	// without the issuer certificate, an embedded certificate is assumed to be the signer
+	if ctx.Resp.Certificate != nil {
+		return ctx.Resp.Certificate, "embedded certificate"
+	}
+
+	return nil, ""
+}
+
+// publicKeyHash returns the SHA-1 hash of the subjectPublicKey BIT STRING of a certificate,
+// excluding its tag, length and number of unused bits, as used in ResponderID byKey
+func publicKeyHash(cert *x509.Certificate) ([]byte, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+
+	_, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing subject public key info: %w", err)
+	}
+
+	hash := sha1.Sum(spki.PublicKey.RightAlign())
+	return hash[:], nil
+}
+
+// LintResponderIDMatchesSigner checks that the ResponderID of an OCSP Response, byName or byKey,
+// identifies the certificate whose key signed it
+// Source: RFC 6960 Section 4.2.2.3
+func LintResponderIDMatchesSigner(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	signer, signerType := getSigner(ctx)
+	if signer == nil {
+		return NotApplicable, "Certificate that signed the OCSP Response is not available"
+	}
+
+	if resp.ResponderKeyHash != nil {
+		keyHash, err := publicKeyHash(signer)
+		if err != nil {
+			return Error, fmt.Sprintf("Could not hash the public key of the %s: %s", signerType, err.Error())
+		}
+
+		if !bytes.Equal(resp.ResponderKeyHash, keyHash) {
+			return Failed, fmt.Sprintf("OCSP Response ResponderID byKey %X does not match the key hash %X of the %s",
+				resp.ResponderKeyHash, keyHash, signerType)
+		}
+
+		return Passed, fmt.Sprintf("OCSP Response ResponderID byKey matches the key hash of the %s", signerType)
+	}
+
+	if !bytes.Equal(resp.RawResponderName, signer.RawSubject) {
+		var name pkix.RDNSequence
+		if _, err := asn1.Unmarshal(resp.RawResponderName, &name); err != nil {
+			// the name cannot be printed, but it still does not match
+			return Failed, fmt.Sprintf("OCSP Response ResponderID byName %X is not a valid name and does not match "+
+				"the subject %s of the %s", resp.RawResponderName, signer.Subject, signerType)
+		}
+		return Failed, fmt.Sprintf("OCSP Response ResponderID byName %s does not match the subject %s of the %s",
+			name, signer.Subject, signerType)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response ResponderID byName matches the subject of the %s", signerType)
+}
+
+// LintResponderIDByKey checks that the ResponderID of an OCSP Response uses byKey rather than byName
+// Source: RFC 5019 Section 2.2.3
+func LintResponderIDByKey(ctx *LintContext) (LintStatus, string) {
+	if ctx.Resp.ResponderKeyHash == nil {
+		return Warn, "OCSP Response ResponderID uses byName instead of byKey"
+	}
+
+	return Passed, "OCSP Response ResponderID uses byKey"
+}
+
+// LintResponderCertEKU checks that a delegated responder certificate has the id-kp-OCSPSigning extended key usage
+// Source: RFC 6960 Section 4.2.2.2
+func LintResponderCertEKU(ctx *LintContext) (LintStatus, string) {
+	cert := getResponderCert(ctx)
+	if cert == nil {
+		return NotApplicable, "OCSP Response is not signed by a delegated responder"
+	}
+
+	for _, eku := range cert.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageOCSPSigning {
+			return Passed, "Delegated responder certificate has the id-kp-OCSPSigning extended key usage"
+		}
+	}
+
+	return Failed, "Delegated responder certificate does not have the id-kp-OCSPSigning extended key usage"
+}
+
+// LintResponderCertIssuer checks that a delegated responder certificate is issued directly by
+// the CA that issued the certificate the OCSP Response is for
+// Source: RFC 6960 Section 4.2.2.2
+func LintResponderCertIssuer(ctx *LintContext) (LintStatus, string) {
+	cert := getResponderCert(ctx)
+	if cert == nil {
+		return NotApplicable, "OCSP Response is not signed by a delegated responder"
+	}
+
+	if ctx.IssuerCert == nil {
+		return NotApplicable, "Issuer certificate is not available to check the delegated responder certificate against"
+	}
+
+	if !bytes.Equal(cert.RawIssuer, ctx.IssuerCert.RawSubject) {
+		return Failed, fmt.Sprintf("Delegated responder certificate is issued by %s instead of the issuer %s",
+			cert.Issuer, ctx.IssuerCert.Subject)
+	}
+
+	err := cert.CheckSignatureFrom(ctx.IssuerCert)
+	if err != nil {
+		return Failed, fmt.Sprintf("Delegated responder certificate is not signed by the issuer %s: %s",
+			ctx.IssuerCert.Subject, err.Error())
+	}
+
+	return Passed, fmt.Sprintf("Delegated responder certificate is issued directly by the issuer %s", ctx.IssuerCert.Subject)
+}
+
+// LintResponderCertValidity checks that a delegated responder certificate is within its
+// validity period at the OCSP Response ProducedAt date
+// Source: RFC 5280 Section 4.1.2.5
+func LintResponderCertValidity(ctx *LintContext) (LintStatus, string) {
+	cert := getResponderCert(ctx)
+	if cert == nil {
+		return NotApplicable, "OCSP Response is not signed by a delegated responder"
+	}
+
+	producedAt := ctx.Resp.ProducedAt
+	if producedAt.Before(cert.NotBefore) || producedAt.After(cert.NotAfter) {
+		return Failed, fmt.Sprintf("Delegated responder certificate valid from %s to %s is not valid at producedAt date %s",
+			cert.NotBefore, cert.NotAfter, producedAt)
+	}
+
+	return Passed, fmt.Sprintf("Delegated responder certificate valid from %s to %s is valid at producedAt date %s",
+		cert.NotBefore, cert.NotAfter, producedAt)
+}
+
+// LintResponderCertNoCheck checks that a delegated responder certificate has the id-pkix-ocsp-nocheck extension
+// Source: CA/B Forum Baseline Requirements Section 4.9.9
+func LintResponderCertNoCheck(ctx *LintContext) (LintStatus, string) {
+	cert := getResponderCert(ctx)
+	if cert == nil {
+		return NotApplicable, "OCSP Response is not signed by a delegated responder"
+	}
+
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(OIDPKIXOCSPNoCheck) {
+			return Passed, "Delegated responder certificate has the id-pkix-ocsp-nocheck extension"
+		}
+	}
+
+	return Failed, "Delegated responder certificate does not have the id-pkix-ocsp-nocheck extension"
+}
+
+// LintResponderCertKeyUsage checks that a delegated responder certificate has the digitalSignature key usage
+// Source: RFC 5280 Section 4.2.1.3
+func LintResponderCertKeyUsage(ctx *LintContext) (LintStatus, string) {
+	cert := getResponderCert(ctx)
+	if cert == nil {
+		return NotApplicable, "OCSP Response is not signed by a delegated responder"
+	}
+
+	if cert.KeyUsage == 0 {
+		return Failed, "Delegated responder certificate has no key usage extension"
+	}
+
+	if cert.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return Failed, "Delegated responder certificate does not have the digitalSignature key usage"
+	}
+
+	return Passed, "Delegated responder certificate has the digitalSignature key usage"
+}
+
+// LintResponderCertLifetime checks that a delegated responder certificate is valid for no more than
+// the responder certificate lifetime limit, as responder certificates with id-pkix-ocsp-nocheck can't be revoked
+// Source: RFC 6960 Section 4.2.2.2.1
+func LintResponderCertLifetime(ctx *LintContext) (LintStatus, string) {
+	cert := getResponderCert(ctx)
+	if cert == nil {
+		return NotApplicable, "OCSP Response is not signed by a delegated responder"
+	}
+
+	limit := ctx.Thresholds.ResponderCertLifetimeLimit
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+
+	if lifetime > limit {
+		return Warn, fmt.Sprintf("Delegated responder certificate is valid for %s, which is more than %s",
+			DurationToString(lifetime.Round(time.Hour)), DurationToString(limit))
+	}
+
+	return Passed, fmt.Sprintf("Delegated responder certificate is valid for %s, which is within %s",
+		DurationToString(lifetime.Round(time.Hour)), DurationToString(limit))
+}
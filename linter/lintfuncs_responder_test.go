@@ -0,0 +1,368 @@
+package linter
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCert creates a certificate from template signed by parent with parentKey, or self signed
+// if parent is nil, and returns it along with its newly generated key
+func newTestCert(template *x509.Certificate, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	if template.SerialNumber == nil {
+		template.SerialNumber = big.NewInt(1)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return cert, key
+}
+
+// newTestIssuer creates a self signed CA certificate and returns it along with its key
+func newTestIssuer(name string) (*x509.Certificate, crypto.Signer) {
+	return newTestCert(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+}
+
+// newResponderTemplate returns the template of a well formed delegated responder certificate
+func newResponderTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		Subject:         pkix.Name{CommonName: "Test Responder"},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(30 * 24 * time.Hour),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+		ExtraExtensions: []pkix.Extension{{Id: OIDPKIXOCSPNoCheck, Value: []byte{0x05, 0x00}}},
+	}
+}
+
+// newResponderLintContext returns a lint context of an OCSP response produced now
+// and signed by a delegated responder certificate issued by issuer
+func newResponderLintContext(template *x509.Certificate, issuer *x509.Certificate, issuerKey crypto.Signer) *LintContext {
+	cert, _ := newTestCert(template, issuer, issuerKey)
+	ctx := newLintContext(&ocsp.Response{Certificate: cert, ProducedAt: time.Now()}, nil)
+	ctx.IssuerCert = issuer
+	return ctx
+}
+
+// TestGetResponderCert tests getResponderCert, which returns the delegated responder certificate
+func TestGetResponderCert(t *testing.T) {
+	issuer, _ := newTestIssuer("Test CA")
+
+	t.Run("No certificate", func(t *testing.T) {
+		if getResponderCert(newLintContext(&ocsp.Response{}, nil)) != nil {
+			t.Errorf("OCSP Response without certificate should not have a delegated responder certificate")
+		}
+	})
+
+	t.Run("Issuer certificate", func(t *testing.T) {
+		ctx := newLintContext(&ocsp.Response{Certificate: issuer}, nil)
+		ctx.IssuerCert = issuer
+		if getResponderCert(ctx) != nil {
+			t.Errorf("OCSP Response with issuer certificate should not have a delegated responder certificate")
+		}
+	})
+}
+
+// TestLintResponderCertEKU tests LintResponderCertEKU, which checks that a delegated
+// responder certificate has the id-kp-OCSPSigning extended key usage
+// Source: RFC 6960 Section 4.2.2.2
+func TestLintResponderCertEKU(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderCertEKU(newResponderLintContext(newResponderTemplate(), issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No delegated responder", func(t *testing.T) {
+		status, info := LintResponderCertEKU(newLintContext(&ocsp.Response{}, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	template := newResponderTemplate()
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	t.Run("No id-kp-OCSPSigning", func(t *testing.T) {
+		status, info := LintResponderCertEKU(newResponderLintContext(template, issuer, issuerKey))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderCertIssuer tests LintResponderCertIssuer, which checks that a delegated
+// responder certificate is issued directly by the issuer
+// Source: RFC 6960 Section 4.2.2.2
+func TestLintResponderCertIssuer(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderCertIssuer(newResponderLintContext(newResponderTemplate(), issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	otherIssuer, otherIssuerKey := newTestIssuer("Other CA")
+	t.Run("Issued by another CA", func(t *testing.T) {
+		ctx := newResponderLintContext(newResponderTemplate(), otherIssuer, otherIssuerKey)
+		ctx.IssuerCert = issuer
+		status, info := LintResponderCertIssuer(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	sameNameIssuer, sameNameIssuerKey := newTestIssuer("Test CA")
+	t.Run("Signed by another key", func(t *testing.T) {
+		ctx := newResponderLintContext(newResponderTemplate(), sameNameIssuer, sameNameIssuerKey)
+		ctx.IssuerCert = issuer
+		status, info := LintResponderCertIssuer(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No issuer certificate", func(t *testing.T) {
+		ctx := newResponderLintContext(newResponderTemplate(), issuer, issuerKey)
+		ctx.IssuerCert = nil
+		status, info := LintResponderCertIssuer(ctx)
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderCertValidity tests LintResponderCertValidity, which checks that a delegated
+// responder certificate is valid at the OCSP Response ProducedAt date
+// Source: RFC 5280 Section 4.1.2.5
+func TestLintResponderCertValidity(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+	ctx := newResponderLintContext(newResponderTemplate(), issuer, issuerKey)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderCertValidity(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Resp.ProducedAt = time.Now().Add(-24 * time.Hour)
+	t.Run("ProducedAt before NotBefore", func(t *testing.T) {
+		status, info := LintResponderCertValidity(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Resp.ProducedAt = time.Now().Add(60 * 24 * time.Hour)
+	t.Run("ProducedAt after NotAfter", func(t *testing.T) {
+		status, info := LintResponderCertValidity(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderCertNoCheck tests LintResponderCertNoCheck, which checks that a delegated
+// responder certificate has the id-pkix-ocsp-nocheck extension
+// Source: CA/B Forum Baseline Requirements Section 4.9.9
+func TestLintResponderCertNoCheck(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderCertNoCheck(newResponderLintContext(newResponderTemplate(), issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	template := newResponderTemplate()
+	template.ExtraExtensions = nil
+	t.Run("No id-pkix-ocsp-nocheck", func(t *testing.T) {
+		status, info := LintResponderCertNoCheck(newResponderLintContext(template, issuer, issuerKey))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderCertKeyUsage tests LintResponderCertKeyUsage, which checks that a delegated
+// responder certificate has the digitalSignature key usage
+// Source: RFC 5280 Section 4.2.1.3
+func TestLintResponderCertKeyUsage(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderCertKeyUsage(newResponderLintContext(newResponderTemplate(), issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	template := newResponderTemplate()
+	template.KeyUsage = x509.KeyUsageKeyEncipherment
+	t.Run("No digitalSignature", func(t *testing.T) {
+		status, info := LintResponderCertKeyUsage(newResponderLintContext(template, issuer, issuerKey))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	template = newResponderTemplate()
+	template.KeyUsage = 0
+	t.Run("No key usage", func(t *testing.T) {
+		status, info := LintResponderCertKeyUsage(newResponderLintContext(template, issuer, issuerKey))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderCertLifetime tests LintResponderCertLifetime, which checks that a delegated
+// responder certificate is not valid for longer than the responder certificate lifetime limit
+// Source: RFC 6960 Section 4.2.2.2.1
+func TestLintResponderCertLifetime(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderCertLifetime(newResponderLintContext(newResponderTemplate(), issuer, issuerKey))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	template := newResponderTemplate()
+	template.NotAfter = template.NotBefore.Add(2 * ResponderCertLifetimeLimit)
+	t.Run("Lifetime too long", func(t *testing.T) {
+		status, info := LintResponderCertLifetime(newResponderLintContext(template, issuer, issuerKey))
+		if status != Warn {
+			t.Errorf("Lint should have warned, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderIDMatchesSigner tests LintResponderIDMatchesSigner, which checks that
+// the ResponderID of an OCSP Response identifies the certificate that signed it
+// Source: RFC 6960 Section 4.2.2.3
+func TestLintResponderIDMatchesSigner(t *testing.T) {
+	issuer, issuerKey := newTestIssuer("Test CA")
+	issuerKeyHash, err := publicKeyHash(issuer)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	t.Run("Issuer byName", func(t *testing.T) {
+		ctx := newLintContext(&ocsp.Response{RawResponderName: issuer.RawSubject}, nil)
+		ctx.IssuerCert = issuer
+		status, info := LintResponderIDMatchesSigner(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Issuer byKey", func(t *testing.T) {
+		ctx := newLintContext(&ocsp.Response{ResponderKeyHash: issuerKeyHash}, nil)
+		ctx.IssuerCert = issuer
+		status, info := LintResponderIDMatchesSigner(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx := newResponderLintContext(newResponderTemplate(), issuer, issuerKey)
+	ctx.Resp.ResponderKeyHash = issuerKeyHash
+	t.Run("Delegated responder identified by issuer key", func(t *testing.T) {
+		status, info := LintResponderIDMatchesSigner(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Resp.ResponderKeyHash = nil
+	ctx.Resp.RawResponderName = issuer.RawSubject
+	t.Run("Delegated responder identified by issuer name", func(t *testing.T) {
+		status, info := LintResponderIDMatchesSigner(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Resp.RawResponderName = ctx.Resp.Certificate.RawSubject
+	t.Run("Delegated responder byName", func(t *testing.T) {
+		status, info := LintResponderIDMatchesSigner(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Resp.RawResponderName = []byte{0x30, 0x01}
+	t.Run("Malformed byName", func(t *testing.T) {
+		status, info := LintResponderIDMatchesSigner(ctx)
+		if status != Failed || !strings.Contains(info, "3001") {
+			t.Errorf("Lint should have failed with the raw name, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No signer", func(t *testing.T) {
+		status, info := LintResponderIDMatchesSigner(newLintContext(&ocsp.Response{ResponderKeyHash: issuerKeyHash}, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintResponderIDByKey tests LintResponderIDByKey, which checks that the
+// ResponderID of an OCSP Response uses byKey
+// Source: RFC 5019 Section 2.2.3
+func TestLintResponderIDByKey(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponderIDByKey(newLintContext(&ocsp.Response{ResponderKeyHash: make([]byte, 20)}, nil))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("byName", func(t *testing.T) {
+		status, info := LintResponderIDByKey(newLintContext(&ocsp.Response{RawResponderName: []byte{0x30, 0x00}}, nil))
+		if status != Warn {
+			t.Errorf("Lint should have warned, instead got status %s: %s", status, info)
+		}
+	})
+}
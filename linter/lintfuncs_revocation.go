@@ -0,0 +1,172 @@
+package linter
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"golang.org/x/crypto/ocsp"
+	"time"
+)
+
+// ReasonCodeEffectiveDate is the date from which the CA/B Forum Baseline Requirements and the
+// Mozilla Root Store Policy restrict the revocation reasons of subscriber certificates
+var ReasonCodeEffectiveDate = time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+// ReasonStrings maps RFC 5280 CRLReason values to their names
+var ReasonStrings = map[int]string{
+	ocsp.Unspecified:          "unspecified",
+	ocsp.KeyCompromise:        "keyCompromise",
+	ocsp.CACompromise:         "cACompromise",
+	ocsp.AffiliationChanged:   "affiliationChanged",
+	ocsp.Superseded:           "superseded",
+	ocsp.CessationOfOperation: "cessationOfOperation",
+	ocsp.CertificateHold:      "certificateHold",
+	ocsp.RemoveFromCRL:        "removeFromCRL",
+	ocsp.PrivilegeWithdrawn:   "privilegeWithdrawn",
+	ocsp.AACompromise:         "aACompromise",
+}
+
+// SubscriberReasons are the revocation reasons allowed for subscriber certificates
+// besides omitting the reason, by the CA/B Forum Baseline Requirements and the Mozilla Root Store Policy
+var SubscriberReasons = map[int]bool{
+	ocsp.KeyCompromise:        true,
+	ocsp.AffiliationChanged:   true,
+	ocsp.Superseded:           true,
+	ocsp.CessationOfOperation: true,
+	ocsp.PrivilegeWithdrawn:   true,
+}
+
+// reasonToString returns the name of a revocation reason and its value
+func reasonToString(reason int) string {
+	name, ok := ReasonStrings[reason]
+	if !ok {
+		name = "unknown"
+	}
+
+	return fmt.Sprintf("%s (%d)", name, reason)
+}
+
+// getRevocationReason returns the revocation reason of a revoked OCSP response and whether it is present
+// The ASN.1 structure is used if available, as ocsp.ParseResponse reports an absent reason as unspecified
+func getRevocationReason(ctx *LintContext) (int, bool, error) {
+	single := getSingleResponse(ctx)
+	if single == nil {
+		return ctx.Resp.RevocationReason, ctx.Resp.RevocationReason != ocsp.Unspecified, nil
+	}
+
+	// revoked is [1] IMPLICIT RevokedInfo, whose contents are the revocation time and the optional [0] reason
+	certStatus := single.CertStatus
+	elems, err := sequenceElements(certStatus.Bytes)
+	if err != nil {
+		return 0, false, fmt.Errorf("Error parsing RevokedInfo: %w", err)
+	}
+
+	if len(elems) < 2 || elems[1].Class != asn1.ClassContextSpecific || elems[1].Tag != 0 {
+		return 0, false, nil
+	}
+
+	var reason asn1.Enumerated
+	_, err = asn1.Unmarshal(elems[1].Bytes, &reason)
+	if err != nil {
+		return 0, false, fmt.Errorf("Error parsing revocation reason: %w", err)
+	}
+
+	return int(reason), true, nil
+}
+
+// LintRevokedAtAfterThisUpdate checks that the RevokedAt date of a revoked OCSP Response is not after its ThisUpdate date
+// Source: RFC 6960 Section 2.4
+func LintRevokedAtAfterThisUpdate(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	if resp.Status != ocsp.Revoked {
+		return NotApplicable, "OCSP Response is not revoked"
+	}
+
+	if resp.RevokedAt.After(resp.ThisUpdate) {
+		return Failed, fmt.Sprintf("OCSP Response revokedAt date %s is after its thisUpdate date %s",
+			resp.RevokedAt, resp.ThisUpdate)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response revokedAt date %s is not after its thisUpdate date %s",
+		resp.RevokedAt, resp.ThisUpdate)
+}
+
+// LintRevokedAtAfterProducedAt checks that the RevokedAt date of a revoked OCSP Response is not after its ProducedAt date
+// Source: RFC 6960 Section 2.4
+func LintRevokedAtAfterProducedAt(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	if resp.Status != ocsp.Revoked {
+		return NotApplicable, "OCSP Response is not revoked"
+	}
+
+	if resp.RevokedAt.After(resp.ProducedAt) {
+		return Failed, fmt.Sprintf("OCSP Response revokedAt date %s is after its producedAt date %s",
+			resp.RevokedAt, resp.ProducedAt)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response revokedAt date %s is not after its producedAt date %s",
+		resp.RevokedAt, resp.ProducedAt)
+}
+
+// LintRevocationReasonValid checks that the revocation reason of a revoked OCSP Response,
+// if present, is a CRLReason value defined by RFC 5280 other than the unused value 7
+// Source: RFC 5280 Section 5.3.1
+func LintRevocationReasonValid(ctx *LintContext) (LintStatus, string) {
+	if ctx.Resp.Status != ocsp.Revoked {
+		return NotApplicable, "OCSP Response is not revoked"
+	}
+
+	reason, present, err := getRevocationReason(ctx)
+	if err != nil {
+		return Error, fmt.Sprintf("Could not get OCSP Response revocation reason: %s", err.Error())
+	}
+
+	if !present {
+		return Passed, "OCSP Response has no revocation reason"
+	}
+
+	if _, ok := ReasonStrings[reason]; !ok {
+		return Failed, fmt.Sprintf("OCSP Response revocation reason %d is not a valid CRLReason", reason)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response revocation reason %s is a valid CRLReason", reasonToString(reason))
+}
+
+// LintRevocationReasonSubscriber checks that the revocation reason of a revoked OCSP Response for a
+// subscriber certificate is either absent or one of keyCompromise, affiliationChanged, superseded,
+// cessationOfOperation and privilegeWithdrawn
+// Source: CA/B Forum Baseline Requirements Section 7.2.2 and Mozilla Root Store Policy Section 6.1.1
+func LintRevocationReasonSubscriber(ctx *LintContext) (LintStatus, string) {
+	resp := ctx.Resp
+
+	if resp.Status != ocsp.Revoked {
+		return NotApplicable, "OCSP Response is not revoked"
+	}
+
+	if ctx.LeafCert != nil && ctx.LeafCert.IsCA {
+		return NotApplicable, "OCSP Response revocation reason lint not applicable to CA certificates"
+	}
+
+	if resp.ProducedAt.Before(ReasonCodeEffectiveDate) {
+		return NotEffective, fmt.Sprintf("OCSP Response was produced before revocation reasons were restricted on %s",
+			ReasonCodeEffectiveDate.Format("2006-01-02"))
+	}
+
+	reason, present, err := getRevocationReason(ctx)
+	if err != nil {
+		return Error, fmt.Sprintf("Could not get OCSP Response revocation reason: %s", err.Error())
+	}
+
+	if !present {
+		return Passed, "OCSP Response for subscriber certificate has no revocation reason"
+	}
+
+	if !SubscriberReasons[reason] {
+		return Failed, fmt.Sprintf("OCSP Response for subscriber certificate has revocation reason %s which is not allowed",
+			reasonToString(reason))
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response for subscriber certificate has allowed revocation reason %s",
+		reasonToString(reason))
+}
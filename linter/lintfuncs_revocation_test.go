@@ -0,0 +1,156 @@
+package linter
+
+import (
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newRevokedLintContext returns a lint context of an OCSP response produced now for a
+// certificate revoked at revokedAt with the given reason, which is omitted if unspecified
+func newRevokedLintContext(revokedAt time.Time, reason int) *LintContext {
+	issuer, issuerKey := newTestIssuer("Test CA")
+
+	return newCreatedLintContext(ocsp.Response{
+		Status:           ocsp.Revoked,
+		SerialNumber:     big.NewInt(1),
+		ThisUpdate:       time.Now().Add(-time.Hour),
+		NextUpdate:       time.Now().Add(24 * time.Hour),
+		RevokedAt:        revokedAt,
+		RevocationReason: reason,
+	}, issuer, issuerKey)
+}
+
+// TestGetRevocationReason tests getRevocationReason, which returns the revocation reason
+// of a revoked OCSP response and whether it is present
+func TestGetRevocationReason(t *testing.T) {
+	revokedAt := time.Now().Add(-2 * time.Hour)
+
+	t.Run("Reason present", func(t *testing.T) {
+		reason, present, err := getRevocationReason(newRevokedLintContext(revokedAt, ocsp.KeyCompromise))
+		if err != nil || !present || reason != ocsp.KeyCompromise {
+			t.Errorf("Should have gotten present reason keyCompromise, instead got %d, %t: %v", reason, present, err)
+		}
+	})
+
+	t.Run("Reason absent", func(t *testing.T) {
+		_, present, err := getRevocationReason(newRevokedLintContext(revokedAt, ocsp.Unspecified))
+		if err != nil || present {
+			t.Errorf("Should have gotten absent reason: %v", err)
+		}
+	})
+
+	t.Run("No ASN.1 structure", func(t *testing.T) {
+		ctx := newRevokedLintContext(revokedAt, ocsp.Superseded)
+		ctx.ASN1 = nil
+		reason, present, err := getRevocationReason(ctx)
+		if err != nil || !present || reason != ocsp.Superseded {
+			t.Errorf("Should have gotten present reason superseded, instead got %d, %t: %v", reason, present, err)
+		}
+	})
+}
+
+// TestLintRevokedAtAfterThisUpdate tests LintRevokedAtAfterThisUpdate, which checks that
+// the RevokedAt date of a revoked OCSP Response is not after its ThisUpdate date
+// Source: RFC 6960 Section 2.4
+func TestLintRevokedAtAfterThisUpdate(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintRevokedAtAfterThisUpdate(newRevokedLintContext(time.Now().Add(-2*time.Hour), ocsp.Unspecified))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not revoked", func(t *testing.T) {
+		status, info := LintRevokedAtAfterThisUpdate(newLintContext(&ocsp.Response{Status: ocsp.Good}, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("RevokedAt after ThisUpdate", func(t *testing.T) {
+		status, info := LintRevokedAtAfterThisUpdate(newRevokedLintContext(time.Now().Add(-30*time.Minute), ocsp.Unspecified))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintRevokedAtAfterProducedAt tests LintRevokedAtAfterProducedAt, which checks that
+// the RevokedAt date of a revoked OCSP Response is not after its ProducedAt date
+// Source: RFC 6960 Section 2.4
+func TestLintRevokedAtAfterProducedAt(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintRevokedAtAfterProducedAt(newRevokedLintContext(time.Now().Add(-2*time.Hour), ocsp.Unspecified))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("RevokedAt after ProducedAt", func(t *testing.T) {
+		status, info := LintRevokedAtAfterProducedAt(newRevokedLintContext(time.Now().Add(time.Hour), ocsp.Unspecified))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintRevocationReasonValid tests LintRevocationReasonValid, which checks that the
+// revocation reason of a revoked OCSP Response is a valid CRLReason
+// Source: RFC 5280 Section 5.3.1
+func TestLintRevocationReasonValid(t *testing.T) {
+	revokedAt := time.Now().Add(-2 * time.Hour)
+
+	for _, reason := range []int{ocsp.Unspecified, ocsp.KeyCompromise, ocsp.CertificateHold, ocsp.AACompromise} {
+		t.Run(reasonToString(reason), func(t *testing.T) {
+			status, info := LintRevocationReasonValid(newRevokedLintContext(revokedAt, reason))
+			if status != Passed {
+				t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	for _, reason := range []int{7, 11} {
+		t.Run(reasonToString(reason), func(t *testing.T) {
+			status, info := LintRevocationReasonValid(newRevokedLintContext(revokedAt, reason))
+			if status != Failed {
+				t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+}
+
+// TestLintRevocationReasonSubscriber tests LintRevocationReasonSubscriber, which checks that the
+// revocation reason of a revoked OCSP Response for a subscriber certificate is allowed
+// Source: CA/B Forum Baseline Requirements Section 7.2.2 and Mozilla Root Store Policy Section 6.1.1
+func TestLintRevocationReasonSubscriber(t *testing.T) {
+	revokedAt := time.Now().Add(-2 * time.Hour)
+
+	for _, reason := range []int{ocsp.Unspecified, ocsp.KeyCompromise, ocsp.Superseded, ocsp.PrivilegeWithdrawn} {
+		t.Run(reasonToString(reason), func(t *testing.T) {
+			status, info := LintRevocationReasonSubscriber(newRevokedLintContext(revokedAt, reason))
+			if status != Passed {
+				t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	for _, reason := range []int{ocsp.CACompromise, ocsp.CertificateHold, ocsp.RemoveFromCRL} {
+		t.Run(reasonToString(reason), func(t *testing.T) {
+			status, info := LintRevocationReasonSubscriber(newRevokedLintContext(revokedAt, reason))
+			if status != Failed {
+				t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	ctx := newRevokedLintContext(revokedAt, ocsp.CertificateHold)
+	ctx.Resp.ProducedAt = ReasonCodeEffectiveDate.Add(-time.Hour)
+	t.Run("Produced before effective date", func(t *testing.T) {
+		status, info := LintRevocationReasonSubscriber(ctx)
+		if status != NotEffective {
+			t.Errorf("Lint should not have been effective, instead got status %s: %s", status, info)
+		}
+	})
+}
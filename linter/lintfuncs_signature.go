@@ -0,0 +1,231 @@
+package linter
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	RSAKeySizeMinimum = 2048 // minimum RSA modulus size in bits
+)
+
+// OIDSignatureRSAPSS is the OID of the id-RSASSA-PSS signature algorithm (RFC 4055 section 3.1)
+var OIDSignatureRSAPSS = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+
+// signatureAlgorithmDetails defines the struct of a signature algorithm identified by its OID
+type signatureAlgorithmDetails struct {
+	name       string                    // name of the algorithm in its defining RFC
+	oid        asn1.ObjectIdentifier     // OID of the algorithm
+	algos      []x509.SignatureAlgorithm // algorithms reported by ocsp.ParseResponse for the OID
+	pubKeyAlgo x509.PublicKeyAlgorithm   // type of the key that signs with the algorithm
+	hash       crypto.Hash               // hash used by the algorithm, 0 if given by its parameters
+}
+
+// signatureAlgorithms are the signature algorithms known to the linter, allowed or not
+var signatureAlgorithms = []signatureAlgorithmDetails{
+	{"md2WithRSAEncryption", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 2}, []x509.SignatureAlgorithm{x509.MD2WithRSA}, x509.RSA, 0},
+	{"md5WithRSAEncryption", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 4}, []x509.SignatureAlgorithm{x509.MD5WithRSA}, x509.RSA, crypto.MD5},
+	{"sha1WithRSAEncryption", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}, []x509.SignatureAlgorithm{x509.SHA1WithRSA}, x509.RSA, crypto.SHA1},
+	{"sha256WithRSAEncryption", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}, []x509.SignatureAlgorithm{x509.SHA256WithRSA}, x509.RSA, crypto.SHA256},
+	{"sha384WithRSAEncryption", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}, []x509.SignatureAlgorithm{x509.SHA384WithRSA}, x509.RSA, crypto.SHA384},
+	{"sha512WithRSAEncryption", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}, []x509.SignatureAlgorithm{x509.SHA512WithRSA}, x509.RSA, crypto.SHA512},
+	{"id-RSASSA-PSS", OIDSignatureRSAPSS, []x509.SignatureAlgorithm{x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS}, x509.RSA, 0},
+	{"id-dsa-with-sha1", asn1.ObjectIdentifier{1, 2, 840, 10040, 4, 3}, []x509.SignatureAlgorithm{x509.DSAWithSHA1}, x509.DSA, crypto.SHA1},
+	{"id-dsa-with-sha256", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 2}, []x509.SignatureAlgorithm{x509.DSAWithSHA256}, x509.DSA, crypto.SHA256},
+	{"ecdsa-with-SHA1", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}, []x509.SignatureAlgorithm{x509.ECDSAWithSHA1}, x509.ECDSA, crypto.SHA1},
+	{"ecdsa-with-SHA256", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}, []x509.SignatureAlgorithm{x509.ECDSAWithSHA256}, x509.ECDSA, crypto.SHA256},
+	{"ecdsa-with-SHA384", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}, []x509.SignatureAlgorithm{x509.ECDSAWithSHA384}, x509.ECDSA, crypto.SHA384},
+	{"ecdsa-with-SHA512", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}, []x509.SignatureAlgorithm{x509.ECDSAWithSHA512}, x509.ECDSA, crypto.SHA512},
+	{"id-Ed25519", asn1.ObjectIdentifier{1, 3, 101, 112}, []x509.SignatureAlgorithm{x509.PureEd25519}, x509.Ed25519, 0},
+}
+
+// AllowedSignatureAlgorithms are the names of the signature algorithms an OCSP Response may be signed with
+var AllowedSignatureAlgorithms = map[string]bool{
+	"sha256WithRSAEncryption": true,
+	"sha384WithRSAEncryption": true,
+	"sha512WithRSAEncryption": true,
+	"id-RSASSA-PSS":           true,
+	"ecdsa-with-SHA256":       true,
+	"ecdsa-with-SHA384":       true,
+}
+
+// AllowedCurves maps the names of the curves an ECDSA signing key may use to the hash it must be used with
+var AllowedCurves = map[string]crypto.Hash{
+	"P-256": crypto.SHA256,
+	"P-384": crypto.SHA384,
+}
+
+// AllowedRSAPSSAlgorithmIdentifiers are the hex encoded DER AlgorithmIdentifiers an RSASSA-PSS signature
+// may use, i.e. SHA-256, SHA-384 or SHA-512 with MGF1 using the same hash and a salt as long as the hash
+var AllowedRSAPSSAlgorithmIdentifiers = []string{
+	"304106092a864886f70d01010a3034a00f300d06096086480165030402010500a11c301a06092a864886f70d010108300d06096086480165030402010500a203020120",
+	"304106092a864886f70d01010a3034a00f300d06096086480165030402020500a11c301a06092a864886f70d010108300d06096086480165030402020500a203020130",
+	"304106092a864886f70d01010a3034a00f300d06096086480165030402030500a11c301a06092a864886f70d010108300d06096086480165030402030500a203020140",
+}
+
+// getSignatureAlgorithm returns the details of the signature algorithm of the OCSP response being linted,
+// or nil if it is unknown, along with its OID if available
+// The ASN.1 structure is used if available, as ocsp.ParseResponse doesn't know every algorithm
+func getSignatureAlgorithm(ctx *LintContext) (*signatureAlgorithmDetails, asn1.ObjectIdentifier) {
+	if ctx.ASN1 != nil && ctx.ASN1.BasicResponse != nil {
+		oid := ctx.ASN1.BasicResponse.SignatureAlgorithm.Algorithm
+		for i := range signatureAlgorithms {
+			if signatureAlgorithms[i].oid.Equal(oid) {
+				return &signatureAlgorithms[i], oid
+			}
+		}
+
+		return nil, oid
+	}
+
+	for i := range signatureAlgorithms {
+		for _, algo := range signatureAlgorithms[i].algos {
+			if algo == ctx.Resp.SignatureAlgorithm {
+				return &signatureAlgorithms[i], signatureAlgorithms[i].oid
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// signatureAlgorithmToString returns the name of a signature algorithm, or its OID if it is unknown
+func signatureAlgorithmToString(details *signatureAlgorithmDetails, oid asn1.ObjectIdentifier) string {
+	if details != nil {
+		return details.name
+	}
+
+	if oid != nil {
+		return fmt.Sprintf("unknown algorithm %s", oid)
+	}
+
+	return "unknown algorithm"
+}
+
+// LintSignatureAlgorithmAllowed checks that an OCSP Response is signed with an allowed algorithm,
+// which rules out MD2, MD5 and SHA-1 based algorithms, DSA and any algorithm unknown to the linter
+// Source: CA/B Forum Baseline Requirements Section 7.1.3.2 and Mozilla Root Store Policy Section 5.1
+func LintSignatureAlgorithmAllowed(ctx *LintContext) (LintStatus, string) {
+	details, oid := getSignatureAlgorithm(ctx)
+	algo := signatureAlgorithmToString(details, oid)
+
+	if details == nil || !AllowedSignatureAlgorithms[details.name] {
+		return Failed, fmt.Sprintf("OCSP Response is signed with %s, which is not allowed", algo)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response is signed with allowed algorithm %s", algo)
+}
+
+// LintSignatureAlgorithmMatchesKey checks that the signature algorithm of an OCSP Response
+// is one used with the type of the public key of the certificate that signed it
+// Source: RFC 5280 Section 4.1.1.2
+func LintSignatureAlgorithmMatchesKey(ctx *LintContext) (LintStatus, string) {
+	signer, signerType := getSigner(ctx)
+	if signer == nil {
+		return NotApplicable, "No certificate that could have signed the OCSP Response is available"
+	}
+
+	details, oid := getSignatureAlgorithm(ctx)
+	if details == nil {
+		return NotApplicable, fmt.Sprintf("OCSP Response is signed with %s", signatureAlgorithmToString(details, oid))
+	}
+
+	if details.pubKeyAlgo != signer.PublicKeyAlgorithm {
+		return Failed, fmt.Sprintf("OCSP Response signature algorithm %s requires a %s key, but the %s has a %s key",
+			details.name, details.pubKeyAlgo, signerType, signer.PublicKeyAlgorithm)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response signature algorithm %s matches the %s key of the %s",
+		details.name, signer.PublicKeyAlgorithm, signerType)
+}
+
+// LintSigningKeyRSASize checks that the RSA key of the certificate that signed an OCSP Response
+// has a modulus of at least the minimum size whose size in bits is divisible by 8
+// Source: CA/B Forum Baseline Requirements Section 6.1.5 and Mozilla Root Store Policy Section 5.1
+func LintSigningKeyRSASize(ctx *LintContext) (LintStatus, string) {
+	signer, signerType := getSigner(ctx)
+	if signer == nil {
+		return NotApplicable, "No certificate that could have signed the OCSP Response is available"
+	}
+
+	key, ok := signer.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return NotApplicable, fmt.Sprintf("The %s does not have an RSA key", signerType)
+	}
+
+	size := key.N.BitLen()
+	if size < RSAKeySizeMinimum {
+		return Failed, fmt.Sprintf("The RSA key of the %s is %d bits, which is less than %d bits",
+			signerType, size, RSAKeySizeMinimum)
+	}
+
+	if size%8 != 0 {
+		return Failed, fmt.Sprintf("The RSA key of the %s is %d bits, which is not divisible by 8", signerType, size)
+	}
+
+	return Passed, fmt.Sprintf("The RSA key of the %s is %d bits", signerType, size)
+}
+
+// LintSigningKeyECDSACurve checks that the ECDSA key of the certificate that signed an OCSP Response
+// uses an allowed curve, and that the response is signed with the hash the curve must be used with
+// Source: Mozilla Root Store Policy Section 5.1
+func LintSigningKeyECDSACurve(ctx *LintContext) (LintStatus, string) {
+	signer, signerType := getSigner(ctx)
+	if signer == nil {
+		return NotApplicable, "No certificate that could have signed the OCSP Response is available"
+	}
+
+	key, ok := signer.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return NotApplicable, fmt.Sprintf("The %s does not have an ECDSA key", signerType)
+	}
+
+	curve := key.Curve.Params().Name
+	hash, ok := AllowedCurves[curve]
+	if !ok {
+		return Failed, fmt.Sprintf("The ECDSA key of the %s uses curve %s, which is not allowed", signerType, curve)
+	}
+
+	// a mismatched key type is reported by LintSignatureAlgorithmMatchesKey
+	details, _ := getSignatureAlgorithm(ctx)
+	if details != nil && details.pubKeyAlgo == x509.ECDSA && details.hash != hash {
+		return Failed, fmt.Sprintf("OCSP Response is signed with %s, but the ECDSA key of the %s uses curve %s, which must be used with %s",
+			details.name, signerType, curve, hash)
+	}
+
+	return Passed, fmt.Sprintf("The ECDSA key of the %s uses allowed curve %s", signerType, curve)
+}
+
+// LintRSAPSSParameters checks that an OCSP Response signed with RSASSA-PSS uses one of the allowed
+// combinations of hash, mask generation function and salt length, encoded exactly as expected
+// Source: Mozilla Root Store Policy Section 5.1.1
+func LintRSAPSSParameters(ctx *LintContext) (LintStatus, string) {
+	basicResp, status, info := getBasicResponse(ctx)
+	if basicResp == nil {
+		return status, info
+	}
+
+	if !basicResp.SignatureAlgorithm.Algorithm.Equal(OIDSignatureRSAPSS) {
+		return NotApplicable, "OCSP Response is not signed with RSASSA-PSS"
+	}
+
+	algorithmID, err := asn1.Marshal(basicResp.SignatureAlgorithm)
+	if err != nil {
+		return Error, fmt.Sprintf("Could not encode the OCSP Response signature algorithm: %s", err.Error())
+	}
+
+	for _, allowed := range AllowedRSAPSSAlgorithmIdentifiers {
+		encoded, _ := hex.DecodeString(allowed)
+		if bytes.Equal(algorithmID, encoded) {
+			return Passed, "OCSP Response RSASSA-PSS parameters are allowed"
+		}
+	}
+
+	return Failed, fmt.Sprintf("OCSP Response RSASSA-PSS parameters %x are not allowed", basicResp.SignatureAlgorithm.Parameters.FullBytes)
+}
@@ -0,0 +1,349 @@
+package linter
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"golang.org/x/crypto/ocsp"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newSignedLintContext returns a lint context of an OCSP response produced now and signed
+// with sigAlgo, or the default algorithm for the key if 0, by an issuer with the given key
+func newSignedLintContext(key crypto.Signer, sigAlgo x509.SignatureAlgorithm) *LintContext {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	issuer, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return newCreatedLintContext(ocsp.Response{
+		Status:             ocsp.Good,
+		SerialNumber:       big.NewInt(1),
+		ThisUpdate:         time.Now().Add(-time.Hour),
+		NextUpdate:         time.Now().Add(24 * time.Hour),
+		SignatureAlgorithm: sigAlgo,
+	}, issuer, key)
+}
+
+// setSignatureAlgorithm replaces the signature algorithm in the ASN.1 structure of the lint context
+func setSignatureAlgorithm(ctx *LintContext, algorithmID string) {
+	der, _ := hex.DecodeString(algorithmID)
+	_, err := asn1.Unmarshal(der, &ctx.ASN1.BasicResponse.SignatureAlgorithm)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// newRSAKey generates an RSA key with a modulus of the given size in bits
+func newRSAKey(bits int) crypto.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return key
+}
+
+// newECDSAKey generates an ECDSA key on the given curve
+func newECDSAKey(curve elliptic.Curve) crypto.Signer {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return key
+}
+
+// TestGetSignatureAlgorithm tests getSignatureAlgorithm, which returns the details of the
+// signature algorithm of an OCSP response
+func TestGetSignatureAlgorithm(t *testing.T) {
+	ctx := newSignedLintContext(newECDSAKey(elliptic.P384()), 0)
+
+	t.Run("From ASN.1 structure", func(t *testing.T) {
+		details, _ := getSignatureAlgorithm(ctx)
+		if details == nil || details.name != "ecdsa-with-SHA384" {
+			t.Errorf("Should have gotten ecdsa-with-SHA384, instead got %v", details)
+		}
+	})
+
+	t.Run("From parsed response", func(t *testing.T) {
+		ctx := newLintContext(&ocsp.Response{SignatureAlgorithm: x509.SHA384WithRSAPSS}, nil)
+		details, _ := getSignatureAlgorithm(ctx)
+		if details == nil || details.name != "id-RSASSA-PSS" {
+			t.Errorf("Should have gotten id-RSASSA-PSS, instead got %v", details)
+		}
+	})
+
+	t.Run("Unknown algorithm", func(t *testing.T) {
+		ctx := newSignedLintContext(newECDSAKey(elliptic.P256()), 0)
+		ctx.ASN1.BasicResponse.SignatureAlgorithm.Algorithm = asn1.ObjectIdentifier{1, 2, 3}
+		details, oid := getSignatureAlgorithm(ctx)
+		if details != nil || !oid.Equal(asn1.ObjectIdentifier{1, 2, 3}) {
+			t.Errorf("Should have gotten unknown algorithm 1.2.3, instead got %v, %s", details, oid)
+		}
+	})
+}
+
+// TestLintSignatureAlgorithmAllowed tests LintSignatureAlgorithmAllowed, which checks that
+// an OCSP Response is signed with an allowed algorithm
+// Source: CA/B Forum Baseline Requirements Section 7.1.3.2 and Mozilla Root Store Policy Section 5.1
+func TestLintSignatureAlgorithmAllowed(t *testing.T) {
+	rsaKey := newRSAKey(2048)
+
+	for _, sigAlgo := range []x509.SignatureAlgorithm{x509.SHA256WithRSA, x509.SHA384WithRSA, x509.SHA512WithRSA} {
+		t.Run(sigAlgo.String(), func(t *testing.T) {
+			status, info := LintSignatureAlgorithmAllowed(newSignedLintContext(rsaKey, sigAlgo))
+			if status != Passed {
+				t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	t.Run("ECDSA", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmAllowed(newSignedLintContext(newECDSAKey(elliptic.P256()), 0))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("RSASSA-PSS", func(t *testing.T) {
+		ctx := newSignedLintContext(rsaKey, 0)
+		setSignatureAlgorithm(ctx, AllowedRSAPSSAlgorithmIdentifiers[0])
+		status, info := LintSignatureAlgorithmAllowed(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("SHA1", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmAllowed(newSignedLintContext(rsaKey, x509.SHA1WithRSA))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("ECDSA with SHA512", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmAllowed(newSignedLintContext(newECDSAKey(elliptic.P256()), x509.ECDSAWithSHA512))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	badAlgos := map[string]string{
+		"MD5":     "300d06092a864886f70d0101040500", // md5WithRSAEncryption
+		"DSA":     "300b06096086480165030403020500", // id-dsa-with-sha256
+		"Unknown": "3004060200010500",               // 0.0.1
+	}
+
+	for name, algorithmID := range badAlgos {
+		ctx := newSignedLintContext(rsaKey, 0)
+		setSignatureAlgorithm(ctx, algorithmID)
+		t.Run(name, func(t *testing.T) {
+			status, info := LintSignatureAlgorithmAllowed(ctx)
+			if status != Failed {
+				t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	t.Run("MD5 from parsed response", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmAllowed(newLintContext(&ocsp.Response{SignatureAlgorithm: x509.MD5WithRSA}, nil))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintSignatureAlgorithmMatchesKey tests LintSignatureAlgorithmMatchesKey, which checks that
+// the signature algorithm of an OCSP Response matches the key type of its signer
+// Source: RFC 5280 Section 4.1.1.2
+func TestLintSignatureAlgorithmMatchesKey(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmMatchesKey(newSignedLintContext(newECDSAKey(elliptic.P256()), 0))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("No signer", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmMatchesKey(newLintContext(&ocsp.Response{}, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx := newSignedLintContext(newECDSAKey(elliptic.P256()), 0)
+	setSignatureAlgorithm(ctx, "300d06092a864886f70d01010b0500") // sha256WithRSAEncryption
+	t.Run("RSA algorithm with ECDSA key", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmMatchesKey(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edCtx := newSignedLintContext(newECDSAKey(elliptic.P256()), 0)
+	edCtx.IssuerCert.PublicKeyAlgorithm = x509.Ed25519
+	edCtx.IssuerCert.PublicKey = edKey.Public()
+	t.Run("ECDSA algorithm with Ed25519 key", func(t *testing.T) {
+		status, info := LintSignatureAlgorithmMatchesKey(edCtx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintSigningKeyRSASize tests LintSigningKeyRSASize, which checks that the RSA key of
+// the signer of an OCSP Response is at least the minimum size and divisible by 8
+// Source: CA/B Forum Baseline Requirements Section 6.1.5 and Mozilla Root Store Policy Section 5.1
+func TestLintSigningKeyRSASize(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintSigningKeyRSASize(newSignedLintContext(newRSAKey(2048), 0))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("ECDSA key", func(t *testing.T) {
+		status, info := LintSigningKeyRSASize(newSignedLintContext(newECDSAKey(elliptic.P256()), 0))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Key too small", func(t *testing.T) {
+		status, info := LintSigningKeyRSASize(newSignedLintContext(newRSAKey(1024), 0))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Key not divisible by 8", func(t *testing.T) {
+		status, info := LintSigningKeyRSASize(newSignedLintContext(newRSAKey(2052), 0))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintSigningKeyECDSACurve tests LintSigningKeyECDSACurve, which checks that the ECDSA key
+// of the signer of an OCSP Response uses an allowed curve with the matching hash
+// Source: Mozilla Root Store Policy Section 5.1
+func TestLintSigningKeyECDSACurve(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			status, info := LintSigningKeyECDSACurve(newSignedLintContext(newECDSAKey(curve), 0))
+			if status != Passed {
+				t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	t.Run("RSA key", func(t *testing.T) {
+		status, info := LintSigningKeyECDSACurve(newSignedLintContext(newRSAKey(2048), 0))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("P-521", func(t *testing.T) {
+		status, info := LintSigningKeyECDSACurve(newSignedLintContext(newECDSAKey(elliptic.P521()), 0))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("P-256 with SHA384", func(t *testing.T) {
+		status, info := LintSigningKeyECDSACurve(newSignedLintContext(newECDSAKey(elliptic.P256()), x509.ECDSAWithSHA384))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintRSAPSSParameters tests LintRSAPSSParameters, which checks that an OCSP Response
+// signed with RSASSA-PSS uses allowed parameters
+// Source: Mozilla Root Store Policy Section 5.1.1
+func TestLintRSAPSSParameters(t *testing.T) {
+	rsaKey := newRSAKey(2048)
+
+	pssAlgos := []x509.SignatureAlgorithm{x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS}
+	for i, sigAlgo := range pssAlgos {
+		ctx := newSignedLintContext(rsaKey, 0)
+		setSignatureAlgorithm(ctx, AllowedRSAPSSAlgorithmIdentifiers[i])
+		t.Run(sigAlgo.String(), func(t *testing.T) {
+			status, info := LintRSAPSSParameters(ctx)
+			if status != Passed {
+				t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	// the allowed encodings are those crypto/x509 signs certificates with
+	for i, sigAlgo := range pssAlgos {
+		template := &x509.Certificate{SerialNumber: big.NewInt(1), SignatureAlgorithm: sigAlgo}
+		der, _ := x509.CreateCertificate(rand.Reader, template, template, rsaKey.Public(), rsaKey)
+		var cert struct {
+			TBSCertificate     asn1.RawValue
+			SignatureAlgorithm asn1.RawValue
+			Signature          asn1.BitString
+		}
+		_, err := asn1.Unmarshal(der, &cert)
+		t.Run(sigAlgo.String()+" encoding", func(t *testing.T) {
+			if err != nil || hex.EncodeToString(cert.SignatureAlgorithm.FullBytes) != AllowedRSAPSSAlgorithmIdentifiers[i] {
+				t.Errorf("Allowed encoding does not match crypto/x509, which uses %x: %v", cert.SignatureAlgorithm.FullBytes, err)
+			}
+		})
+	}
+
+	t.Run("Not RSASSA-PSS", func(t *testing.T) {
+		status, info := LintRSAPSSParameters(newSignedLintContext(rsaKey, 0))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	// SHA-256 with MGF1 using SHA-256 and a salt of 20 bytes
+	ctx := newSignedLintContext(rsaKey, 0)
+	setSignatureAlgorithm(ctx, "304106092a864886f70d01010a3034a00f300d06096086480165030402010500a11c301a06092a864886f70d010108300d06096086480165030402010500a203020114")
+	t.Run("Wrong salt length", func(t *testing.T) {
+		status, info := LintRSAPSSParameters(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	// SHA-256 with MGF1 using SHA-256 and a salt of 32 bytes, with the absent hash parameters encoding
+	ctx = newSignedLintContext(rsaKey, 0)
+	setSignatureAlgorithm(ctx, "303d06092a864886f70d01010a3030a00d300b0609608648016503040201a11a301806092a864886f70d010108300b0609608648016503040201a203020120")
+	t.Run("Hash parameters absent", func(t *testing.T) {
+		status, info := LintRSAPSSParameters(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
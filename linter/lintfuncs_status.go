@@ -0,0 +1,85 @@
+package linter
+
+import (
+	"fmt"
+	"golang.org/x/crypto/ocsp"
+)
+
+// ResponseStatusDescriptions maps unsuccessful OCSP responseStatus values to what they mean (RFC 6960 section 2.3)
+var ResponseStatusDescriptions = map[ocsp.ResponseStatus]string{
+	ocsp.Malformed:         "the responder considers the request malformed",
+	ocsp.InternalError:     "the responder reached an inconsistent internal state",
+	ocsp.TryLater:          "the responder is temporarily unable to respond",
+	ocsp.SignatureRequired: "the responder requires the request to be signed",
+	ocsp.Unauthorized:      "the responder is not authorized to respond for the certificate",
+}
+
+// responseStatusDescription returns what an unsuccessful OCSP responseStatus value means
+func responseStatusDescription(status ocsp.ResponseStatus) string {
+	if description, ok := ResponseStatusDescriptions[status]; ok {
+		return description
+	}
+
+	return "the responder used a status that RFC 6960 does not assign"
+}
+
+// LintResponseStatus checks that the responseStatus of an OCSP Response is successful,
+// as any other status means the responder did not give the status of the certificate
+// Source: RFC 6960 Section 2.3
+func LintResponseStatus(ctx *LintContext) (LintStatus, string) {
+	status := ctx.ResponseStatus
+
+	if status != ocsp.Success {
+		return Failed, fmt.Sprintf("OCSP Response status is %s, meaning %s",
+			ResponseStatusString(status), responseStatusDescription(status))
+	}
+
+	return Passed, "OCSP Response status is successful"
+}
+
+// LintUnauthorizedForServedCert checks that an OCSP responder named in the authority information
+// access extension of a certificate does not respond unauthorized for it, as the CA must provide
+// the status of every certificate it issued
+// Source: CA/B Forum Baseline Requirements Section 4.10.2
+func LintUnauthorizedForServedCert(ctx *LintContext) (LintStatus, string) {
+	if ctx.ResponseStatus != ocsp.Unauthorized {
+		return NotApplicable, "OCSP Response status is not unauthorized"
+	}
+
+	if ctx.LeafCert == nil || ctx.ResponderURL == "" {
+		return NotApplicable, "OCSP Response was not fetched for a known certificate"
+	}
+
+	for _, server := range ctx.LeafCert.OCSPServer {
+		if server == ctx.ResponderURL {
+			return Failed, fmt.Sprintf("OCSP responder %s returned unauthorized for a certificate that names it as its OCSP responder",
+				ctx.ResponderURL)
+		}
+	}
+
+	return Passed, fmt.Sprintf("OCSP responder %s returned unauthorized for a certificate that does not name it as its OCSP responder",
+		ctx.ResponderURL)
+}
+
+// LintUnsuccessfulResponseBytes checks that an OCSP Response whose responseStatus is not successful has no responseBytes
+// Source: RFC 6960 Section 4.2.1
+func LintUnsuccessfulResponseBytes(ctx *LintContext) (LintStatus, string) {
+	if ctx.ResponseStatus == ocsp.Success {
+		return NotApplicable, "OCSP Response status is successful"
+	}
+
+	if ctx.RawResp == nil {
+		return NotApplicable, "Raw OCSP Response is not available"
+	}
+
+	if ctx.ASN1 == nil {
+		return Error, "Could not parse the ASN.1 structure of the OCSP Response"
+	}
+
+	status := ResponseStatusString(ctx.ResponseStatus)
+	if ctx.ASN1.OCSPResponse.ResponseBytes.ResponseType != nil {
+		return Failed, fmt.Sprintf("OCSP Response with status %s has responseBytes", status)
+	}
+
+	return Passed, fmt.Sprintf("OCSP Response with status %s has no responseBytes", status)
+}
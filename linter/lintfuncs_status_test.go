@@ -0,0 +1,116 @@
+package linter
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"golang.org/x/crypto/ocsp"
+	"strings"
+	"testing"
+)
+
+// newUnsuccessfulLintContext returns a lint context of an OCSP response with the given
+// responseStatus, fetched from responderURL for leaf, which has responseBytes if withBytes
+func newUnsuccessfulLintContext(status ocsp.ResponseStatus, withBytes bool, leaf *x509.Certificate, responderURL string) *LintContext {
+	elems := [][]byte{derMarshal(asn1.Enumerated(status))}
+	if withBytes {
+		elems = append(elems, derExplicit(0, derSeq(derMarshal(asn1.ObjectIdentifier{1, 2, 3}), derMarshal([]byte{1}))))
+	}
+
+	rawResp := derSeq(elems...)
+	ctx := newLintContext(nil, leaf)
+	ctx.RawResp = rawResp
+	ctx.ASN1, _ = ParseASN1Response(rawResp)
+	ctx.ResponseStatus = status
+	ctx.ResponderURL = responderURL
+	return ctx
+}
+
+// TestLintResponseStatus tests LintResponseStatus, which checks that the
+// responseStatus of an OCSP Response is successful
+// Source: RFC 6960 Section 2.3
+func TestLintResponseStatus(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintResponseStatus(newLintContext(&ocsp.Response{}, nil))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	for _, respStatus := range []ocsp.ResponseStatus{ocsp.Malformed, ocsp.InternalError, ocsp.TryLater, ocsp.SignatureRequired, ocsp.Unauthorized} {
+		t.Run(ResponseStatusStrings[respStatus], func(t *testing.T) {
+			status, info := LintResponseStatus(newUnsuccessfulLintContext(respStatus, false, nil, ""))
+			if status != Failed {
+				t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+			}
+		})
+	}
+
+	t.Run("Unassigned status", func(t *testing.T) {
+		status, info := LintResponseStatus(newUnsuccessfulLintContext(4, false, nil, ""))
+		if status != Failed || !strings.Contains(info, "unknown (4)") {
+			t.Errorf("Lint should have failed naming status 4, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintUnauthorizedForServedCert tests LintUnauthorizedForServedCert, which checks that an
+// OCSP responder named by a certificate does not respond unauthorized for it
+// Source: CA/B Forum Baseline Requirements Section 4.10.2
+func TestLintUnauthorizedForServedCert(t *testing.T) {
+	responderURL := "http://ocsp.example.com"
+	leaf := &x509.Certificate{OCSPServer: []string{responderURL}}
+
+	t.Run("Responder not named by certificate", func(t *testing.T) {
+		status, info := LintUnauthorizedForServedCert(newUnsuccessfulLintContext(ocsp.Unauthorized, false, leaf, "http://other.example.com"))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not unauthorized", func(t *testing.T) {
+		status, info := LintUnauthorizedForServedCert(newUnsuccessfulLintContext(ocsp.TryLater, false, leaf, responderURL))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Not fetched", func(t *testing.T) {
+		status, info := LintUnauthorizedForServedCert(newUnsuccessfulLintContext(ocsp.Unauthorized, false, leaf, ""))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Responder named by certificate", func(t *testing.T) {
+		status, info := LintUnauthorizedForServedCert(newUnsuccessfulLintContext(ocsp.Unauthorized, false, leaf, responderURL))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintUnsuccessfulResponseBytes tests LintUnsuccessfulResponseBytes, which checks that an
+// OCSP Response whose responseStatus is not successful has no responseBytes
+// Source: RFC 6960 Section 4.2.1
+func TestLintUnsuccessfulResponseBytes(t *testing.T) {
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintUnsuccessfulResponseBytes(newUnsuccessfulLintContext(ocsp.TryLater, false, nil, ""))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Successful", func(t *testing.T) {
+		status, info := LintUnsuccessfulResponseBytes(newLintContext(&ocsp.Response{}, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("Has responseBytes", func(t *testing.T) {
+		status, info := LintUnsuccessfulResponseBytes(newUnsuccessfulLintContext(ocsp.TryLater, true, nil, ""))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
@@ -1,29 +1,65 @@
 package linter
 
 import (
+	"crypto"
 	"crypto/x509"
 	"fmt"
 	"github.com/googleinterns/ocsp-response-linter/ocsptools"
+	"golang.org/x/crypto/ocsp"
 	"testing"
 	"time"
 )
 
 const (
-	RespBadDates = "../testdata/resps/oldfbresp" // basic OCSP response
+	RespBadDates     = "../testdata/resps/oldfbresp"        // basic OCSP response
+	RespUnauthorized = "../testdata/resps/unauthorizedresp" // OCSP response with status unauthorized
+	RespMultiple     = "../testdata/resps/multiresp"        // OCSP response with SingleResponses for serial numbers 1 and 2
 )
 
+// newLintContext returns a lint context for the given response and certificate
+// evaluated at the current time with the default thresholds
+func newLintContext(resp *ocsp.Response, leafCert *x509.Certificate) *LintContext {
+	return &LintContext{
+		Resp:       resp,
+		LeafCert:   leafCert,
+		Now:        time.Now(),
+		Thresholds: &DefaultThresholds,
+	}
+}
+
+// newCreatedLintContext returns a lint context for an OCSP response created from template, issued by issuer
+// and signed with signerKey, with its raw bytes and ASN.1 structure
+func newCreatedLintContext(template ocsp.Response, issuer *x509.Certificate, signerKey crypto.Signer) *LintContext {
+	rawResp, err := ocsp.CreateResponse(issuer, issuer, template, signerKey)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// the signature is not checked, as signerKey need not be the key of issuer
+	resp, err := ocsp.ParseResponse(rawResp, nil)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	ctx := newLintContext(resp, nil)
+	ctx.IssuerCert = issuer
+	ctx.RawResp = rawResp
+	ctx.ASN1, _ = ParseASN1Response(rawResp)
+	return ctx
+}
+
 // TestCheckSignature tests CheckSignature, which checks that an
 // OCSP Response signature is present and not signed with an algorithm
 // that uses SHA-1
 // Source: Apple Lints 10 & 12
 func TestCheckSignature(t *testing.T) {
-	ocspResp, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
 	if err != nil {
 		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
 	}
 
 	t.Run("Happy Path", func(t *testing.T) {
-		status, info := CheckSignature(ocspResp, nil)
+		status, info := CheckSignature(newLintContext(ocspResp, nil))
 		if status != Passed {
 			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
 		}
@@ -31,7 +67,7 @@ func TestCheckSignature(t *testing.T) {
 
 	ocspResp.SignatureAlgorithm = x509.SHA1WithRSA
 	t.Run("SHA1 signature algorithm", func(t *testing.T) {
-		status, info := CheckSignature(ocspResp, nil)
+		status, info := CheckSignature(newLintContext(ocspResp, nil))
 		if status != Failed {
 			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
 		}
@@ -39,7 +75,7 @@ func TestCheckSignature(t *testing.T) {
 
 	ocspResp.Signature = nil
 	t.Run("No signature", func(t *testing.T) {
-		status, info := CheckSignature(ocspResp, nil)
+		status, info := CheckSignature(newLintContext(ocspResp, nil))
 		if status != Failed {
 			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
 		}
@@ -50,13 +86,13 @@ func TestCheckSignature(t *testing.T) {
 // OCSP Response ProducedAt date is not too far in the past
 // Source: Apple Lints 03 & 05
 func TestLintProducedAtDate(t *testing.T) {
-	ocspResp, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
 	if err != nil {
 		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
 	}
 
 	t.Run("Old ProducedAt date", func(t *testing.T) {
-		status, info := LintProducedAtDate(ocspResp, nil)
+		status, info := LintProducedAtDate(newLintContext(ocspResp, nil))
 		if status != Failed {
 			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
 		}
@@ -65,7 +101,7 @@ func TestLintProducedAtDate(t *testing.T) {
 	ocspResp.ProducedAt = time.Now()
 
 	t.Run("Happy path", func(t *testing.T) {
-		status, info := LintProducedAtDate(ocspResp, nil)
+		status, info := LintProducedAtDate(newLintContext(ocspResp, nil))
 		if status != Passed {
 			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
 		}
@@ -76,13 +112,13 @@ func TestLintProducedAtDate(t *testing.T) {
 // OCSP Response ThisUpdate date is not too far in the past
 // Source: Apple Lints 03 & 05
 func TestLintThisUpdateDate(t *testing.T) {
-	ocspResp, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
 	if err != nil {
 		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
 	}
 
 	t.Run("Old ThisUpdate date", func(t *testing.T) {
-		status, info := LintThisUpdateDate(ocspResp, nil)
+		status, info := LintThisUpdateDate(newLintContext(ocspResp, nil))
 		if status != Failed {
 			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
 		}
@@ -91,7 +127,7 @@ func TestLintThisUpdateDate(t *testing.T) {
 	ocspResp.ThisUpdate = time.Now()
 
 	t.Run("Happy path", func(t *testing.T) {
-		status, info := LintThisUpdateDate(ocspResp, nil)
+		status, info := LintThisUpdateDate(newLintContext(ocspResp, nil))
 		if status != Passed {
 			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
 		}
@@ -102,13 +138,13 @@ func TestLintThisUpdateDate(t *testing.T) {
 // NextUpdate date is no more than NextUpdateLimit in the future of its ThisUpdate date
 // Source: Apple Lint 04
 func TestLintNextUpdateDate(t *testing.T) {
-	ocspResp, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
 	if err != nil {
 		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
 	}
 
 	t.Run("Happy path", func(t *testing.T) {
-		status, info := LintNextUpdateDate(ocspResp, nil)
+		status, info := LintNextUpdateDate(newLintContext(ocspResp, nil))
 		if status != Passed {
 			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
 		}
@@ -116,7 +152,200 @@ func TestLintNextUpdateDate(t *testing.T) {
 
 	ocspResp.NextUpdate = time.Now()
 	t.Run("NextUpdate date too far in the future", func(t *testing.T) {
-		status, info := LintNextUpdateDate(ocspResp, nil)
+		status, info := LintNextUpdateDate(newLintContext(ocspResp, nil))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("CA certificate", func(t *testing.T) {
+		status, info := LintNextUpdateDate(newLintContext(ocspResp, &x509.Certificate{IsCA: true}))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	ocspResp.NextUpdate = time.Time{}
+	t.Run("No NextUpdate date", func(t *testing.T) {
+		status, info := LintNextUpdateDate(newLintContext(ocspResp, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintNextUpdateMissing tests LintNextUpdateMissing, which checks that an
+// OCSP Response for a subscriber certificate has a NextUpdate date
+// Source: CA/B Forum Baseline Requirements Section 4.9.10
+func TestLintNextUpdateMissing(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintNextUpdateMissing(newLintContext(ocspResp, nil))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ocspResp.NextUpdate = time.Time{}
+	t.Run("No NextUpdate date", func(t *testing.T) {
+		status, info := LintNextUpdateMissing(newLintContext(ocspResp, nil))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("CA certificate", func(t *testing.T) {
+		status, info := LintNextUpdateMissing(newLintContext(ocspResp, &x509.Certificate{IsCA: true}))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintValidityIntervalTooShort tests LintValidityIntervalTooShort, which checks that
+// the validity interval of an OCSP Response is at least the NextUpdate minimum
+// Source: CA/B Forum Baseline Requirements Section 4.9.10
+func TestLintValidityIntervalTooShort(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintValidityIntervalTooShort(newLintContext(ocspResp, nil))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	// the validity interval is inclusive, so this is exactly the minimum
+	ocspResp.NextUpdate = ocspResp.ThisUpdate.Add(NextUpdateMinimumSubscriber - time.Second)
+	t.Run("Validity interval of exactly the minimum", func(t *testing.T) {
+		status, info := LintValidityIntervalTooShort(newLintContext(ocspResp, nil))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ocspResp.NextUpdate = ocspResp.ThisUpdate.Add(time.Hour)
+	t.Run("Validity interval too short", func(t *testing.T) {
+		status, info := LintValidityIntervalTooShort(newLintContext(ocspResp, nil))
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	t.Run("CA certificate", func(t *testing.T) {
+		status, info := LintValidityIntervalTooShort(newLintContext(ocspResp, &x509.Certificate{IsCA: true}))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+
+	ocspResp.NextUpdate = time.Time{}
+	t.Run("No NextUpdate date", func(t *testing.T) {
+		status, info := LintValidityIntervalTooShort(newLintContext(ocspResp, nil))
+		if status != NotApplicable {
+			t.Errorf("Lint should have been not applicable, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintProducedAtInFuture tests LintProducedAtInFuture, which checks that an
+// OCSP Response ProducedAt date is not in the future beyond the clock skew tolerance
+// Source: RFC 6960 Section 4.2.2.1
+func TestLintProducedAtInFuture(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	ctx := newLintContext(ocspResp, nil)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintProducedAtInFuture(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Now = ocspResp.ProducedAt.Add(-ClockSkewTolerance)
+	t.Run("ProducedAt date within clock skew tolerance", func(t *testing.T) {
+		status, info := LintProducedAtInFuture(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Now = ocspResp.ProducedAt.Add(-time.Hour)
+	t.Run("ProducedAt date in the future", func(t *testing.T) {
+		status, info := LintProducedAtInFuture(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintThisUpdateInFuture tests LintThisUpdateInFuture, which checks that an
+// OCSP Response ThisUpdate date is not in the future beyond the clock skew tolerance
+// Source: RFC 6960 Section 4.2.2.1
+func TestLintThisUpdateInFuture(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	ctx := newLintContext(ocspResp, nil)
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintThisUpdateInFuture(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ctx.Now = ocspResp.ThisUpdate.Add(-time.Hour)
+	t.Run("ThisUpdate date in the future", func(t *testing.T) {
+		status, info := LintThisUpdateInFuture(ctx)
+		if status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
+		}
+	})
+
+	thresholds := DefaultThresholds
+	thresholds.ClockSkewTolerance = 2 * time.Hour
+	ctx.Thresholds = &thresholds
+	t.Run("ThisUpdate date within larger clock skew tolerance", func(t *testing.T) {
+		status, info := LintThisUpdateInFuture(ctx)
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+}
+
+// TestLintThisUpdateAfterProducedAt tests LintThisUpdateAfterProducedAt, which checks that
+// an OCSP Response ThisUpdate date is not after its ProducedAt date
+// Source: RFC 6960 Section 2.4
+func TestLintThisUpdateAfterProducedAt(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	t.Run("Happy path", func(t *testing.T) {
+		status, info := LintThisUpdateAfterProducedAt(newLintContext(ocspResp, nil))
+		if status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", status, info)
+		}
+	})
+
+	ocspResp.ThisUpdate = ocspResp.ProducedAt.Add(time.Second)
+	t.Run("ThisUpdate date after ProducedAt date", func(t *testing.T) {
+		status, info := LintThisUpdateAfterProducedAt(newLintContext(ocspResp, nil))
 		if status != Failed {
 			t.Errorf("Lint should have failed, instead got status %s: %s", status, info)
 		}
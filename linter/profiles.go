@@ -0,0 +1,310 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Thresholds defines the struct of the time limits that lints check against
+type Thresholds struct {
+	ProducedAtLimitSubscriber   time.Duration // how far in the past producedAt may be for subscriber certificates
+	ThisUpdateLimitSubscriber   time.Duration // how far in the past thisUpdate may be for subscriber certificates
+	ProducedAtLimitCA           time.Duration // how far in the past producedAt may be for subordinate CA certificates
+	ThisUpdateLimitCA           time.Duration // how far in the past thisUpdate may be for subordinate CA certificates
+	NextUpdateLimitSubscriber   time.Duration // how far after thisUpdate nextUpdate may be for subscriber certificates
+	ClockSkewTolerance          time.Duration // how far in the future producedAt and thisUpdate may be
+	NextUpdateMinimumSubscriber time.Duration // how far after thisUpdate nextUpdate must at least be for subscriber certificates
+	ResponderCertLifetimeLimit  time.Duration // how long delegated responder certificates may be valid for
+}
+
+// DefaultThresholds are the thresholds used when no profile is specified
+var DefaultThresholds = Thresholds{
+	ProducedAtLimitSubscriber:   ProducedAtLimitSubscriber,
+	ThisUpdateLimitSubscriber:   ThisUpdateLimitSubscriber,
+	ProducedAtLimitCA:           ProducedAtLimitCA,
+	ThisUpdateLimitCA:           ThisUpdateLimitCA,
+	NextUpdateLimitSubscriber:   NextUpdateLimitSubscriber,
+	ClockSkewTolerance:          ClockSkewTolerance,
+	NextUpdateMinimumSubscriber: NextUpdateMinimumSubscriber,
+	ResponderCertLifetimeLimit:  ResponderCertLifetimeLimit,
+}
+
+// Profile defines the struct of a named set of lints and thresholds for a policy regime
+type Profile struct {
+	Name        string     // name of the profile, used to select it
+	Description string     // description of the policy regime
+	LintIDs     []string   // ids of the lints in the profile
+	Thresholds  Thresholds // the time limits the lints check against
+}
+
+// Lints returns the registered lints in the profile, in the order they were registered
+// A profile without lints is an error, as linting with it would always pass
+func (p *Profile) Lints() ([]*LintStruct, error) {
+	if len(p.LintIDs) == 0 {
+		return nil, fmt.Errorf("Profile %s has no lints", p.Name)
+	}
+
+	return FilterLints(Lints, p.LintIDs, nil, nil)
+}
+
+// Profiles maps profile names to the profiles
+var Profiles = map[string]*Profile{
+	"apple": {
+		Name:        "apple",
+		Description: "Apple's OCSP Lints and Test Cases",
+		LintIDs: []string{
+			"e_ocsp_signature_missing_or_sha1",
+			"e_ocsp_produced_at_too_old",
+			"e_ocsp_this_update_too_old",
+			"e_ocsp_next_update_too_far_after_this_update",
+			"e_ocsp_produced_at_in_future",
+			"e_ocsp_this_update_in_future",
+			"e_ocsp_this_update_after_produced_at",
+			"e_ocsp_response_status_not_successful",
+			"e_ocsp_unsuccessful_response_has_response_bytes",
+			"e_ocsp_response_type_not_basic",
+			"e_ocsp_response_version_not_v1",
+			"e_ocsp_unexpected_fields",
+			"e_ocsp_der_default_version_encoded",
+			"e_ocsp_der_extension_critical_false_encoded",
+			"e_ocsp_der_generalized_time_not_canonical",
+		},
+		Thresholds: Thresholds{
+			ProducedAtLimitSubscriber: 96 * time.Hour,   // Apple Lints 03 & 05: 4 days
+			ThisUpdateLimitSubscriber: 96 * time.Hour,   // Apple Lints 03 & 05: 4 days
+			ProducedAtLimitCA:         8760 * time.Hour, // Apple Lints 03 & 05: 365 days
+			ThisUpdateLimitCA:         8760 * time.Hour, // Apple Lints 03 & 05: 365 days
+			NextUpdateLimitSubscriber: 240 * time.Hour,  // Apple Lint 04: 10 days
+			ClockSkewTolerance:        5 * time.Minute,
+			// Apple's lints set no minimum validity interval or delegated responder certificate lifetime,
+			// so the profile has no lints that check them
+		},
+	},
+	"cabf": {
+		Name:        "cabf",
+		Description: "CA/Browser Forum Baseline Requirements section 4.9.10",
+		LintIDs: []string{
+			"e_ocsp_signature_missing_or_sha1",
+			"e_ocsp_signature_algorithm_not_allowed",
+			"e_ocsp_signature_algorithm_does_not_match_key",
+			"e_ocsp_signing_key_rsa_size_invalid",
+			"e_ocsp_produced_at_too_old",
+			"e_ocsp_this_update_too_old",
+			"e_ocsp_next_update_too_far_after_this_update",
+			"e_ocsp_next_update_missing",
+			"e_ocsp_validity_interval_too_short",
+			"e_ocsp_produced_at_in_future",
+			"e_ocsp_this_update_in_future",
+			"e_ocsp_this_update_after_produced_at",
+			"e_ocsp_responder_cert_missing_ocsp_signing_eku",
+			"e_ocsp_responder_cert_not_issued_by_issuer",
+			"e_ocsp_responder_id_does_not_match_signer",
+			"n_ocsp_certs_includes_issuer",
+			"n_ocsp_certs_includes_root",
+			"n_ocsp_certs_multiple",
+			"w_ocsp_certs_cert_did_not_sign",
+			"e_ocsp_cert_id_does_not_match_request",
+			"w_ocsp_single_response_not_requested",
+			"e_ocsp_nonce_does_not_match_request",
+			"e_ocsp_revoked_at_after_this_update",
+			"e_ocsp_revoked_at_after_produced_at",
+			"e_ocsp_revocation_reason_invalid",
+			"e_ocsp_revocation_reason_not_allowed_for_subscriber",
+			"e_ocsp_responder_cert_not_valid_at_produced_at",
+			"e_ocsp_responder_cert_missing_ocsp_nocheck",
+			"e_ocsp_responder_cert_missing_digital_signature_key_usage",
+			"w_ocsp_responder_cert_lifetime_too_long",
+			"e_ocsp_response_extension_critical_unknown",
+			"e_ocsp_single_extension_critical_unknown",
+			"e_ocsp_nonce_invalid",
+			"e_ocsp_archive_cutoff_invalid",
+			"e_ocsp_crl_references_invalid",
+			"e_ocsp_response_status_not_successful",
+			"e_ocsp_response_status_unauthorized_for_served_cert",
+			"e_ocsp_unsuccessful_response_has_response_bytes",
+			"e_ocsp_response_type_not_basic",
+			"e_ocsp_response_version_not_v1",
+			"e_ocsp_unexpected_fields",
+			"e_ocsp_der_default_version_encoded",
+			"e_ocsp_der_extension_critical_false_encoded",
+			"e_ocsp_der_generalized_time_not_canonical",
+		},
+		Thresholds: Thresholds{
+			ProducedAtLimitSubscriber:   96 * time.Hour,   // BR 4.9.10: updated at least every 4 days
+			ThisUpdateLimitSubscriber:   96 * time.Hour,   // BR 4.9.10: updated at least every 4 days
+			ProducedAtLimitCA:           8760 * time.Hour, // BR 4.9.10: updated at least every 12 months
+			ThisUpdateLimitCA:           8760 * time.Hour, // BR 4.9.10: updated at least every 12 months
+			NextUpdateLimitSubscriber:   240 * time.Hour,  // BR 4.9.10: validity interval of at most 10 days
+			ClockSkewTolerance:          5 * time.Minute,
+			NextUpdateMinimumSubscriber: 8 * time.Hour,    // BR 4.9.10: validity interval of at least 8 hours
+			ResponderCertLifetimeLimit:  8760 * time.Hour, // the BRs set no limit, RFC 6960 asks for a short lifetime
+		},
+	},
+	"mozilla": {
+		Name:        "mozilla",
+		Description: "Mozilla Root Store Policy, on top of the CA/Browser Forum Baseline Requirements",
+		LintIDs: []string{
+			"e_ocsp_signature_missing_or_sha1",
+			"e_ocsp_signature_algorithm_not_allowed",
+			"e_ocsp_signature_algorithm_does_not_match_key",
+			"e_ocsp_signing_key_rsa_size_invalid",
+			"e_ocsp_signing_key_ecdsa_curve_not_allowed",
+			"e_ocsp_signature_rsa_pss_params_invalid",
+			"e_ocsp_produced_at_too_old",
+			"e_ocsp_this_update_too_old",
+			"e_ocsp_next_update_too_far_after_this_update",
+			"e_ocsp_next_update_missing",
+			"e_ocsp_validity_interval_too_short",
+			"e_ocsp_produced_at_in_future",
+			"e_ocsp_this_update_in_future",
+			"e_ocsp_this_update_after_produced_at",
+			"e_ocsp_responder_cert_missing_ocsp_signing_eku",
+			"e_ocsp_responder_cert_not_issued_by_issuer",
+			"e_ocsp_responder_id_does_not_match_signer",
+			"n_ocsp_certs_includes_issuer",
+			"n_ocsp_certs_includes_root",
+			"n_ocsp_certs_multiple",
+			"w_ocsp_certs_cert_did_not_sign",
+			"e_ocsp_cert_id_does_not_match_request",
+			"w_ocsp_single_response_not_requested",
+			"e_ocsp_nonce_does_not_match_request",
+			"e_ocsp_revoked_at_after_this_update",
+			"e_ocsp_revoked_at_after_produced_at",
+			"e_ocsp_revocation_reason_invalid",
+			"e_ocsp_revocation_reason_not_allowed_for_subscriber",
+			"e_ocsp_responder_cert_not_valid_at_produced_at",
+			"e_ocsp_responder_cert_missing_ocsp_nocheck",
+			"e_ocsp_responder_cert_missing_digital_signature_key_usage",
+			"w_ocsp_responder_cert_lifetime_too_long",
+			"e_ocsp_response_extension_critical_unknown",
+			"e_ocsp_single_extension_critical_unknown",
+			"e_ocsp_nonce_invalid",
+			"e_ocsp_archive_cutoff_invalid",
+			"e_ocsp_crl_references_invalid",
+			"e_ocsp_response_status_not_successful",
+			"e_ocsp_response_status_unauthorized_for_served_cert",
+			"e_ocsp_unsuccessful_response_has_response_bytes",
+			"e_ocsp_response_type_not_basic",
+			"e_ocsp_response_version_not_v1",
+			"e_ocsp_unexpected_fields",
+			"e_ocsp_der_default_version_encoded",
+			"e_ocsp_der_extension_critical_false_encoded",
+			"e_ocsp_der_generalized_time_not_canonical",
+		},
+		Thresholds: Thresholds{
+			// Mozilla Root Store Policy Section 2.3 requires following the BRs, so their limits apply
+			ProducedAtLimitSubscriber:   96 * time.Hour,   // BR 4.9.10: updated at least every 4 days
+			ThisUpdateLimitSubscriber:   96 * time.Hour,   // BR 4.9.10: updated at least every 4 days
+			ProducedAtLimitCA:           8760 * time.Hour, // BR 4.9.10: updated at least every 12 months
+			ThisUpdateLimitCA:           8760 * time.Hour, // BR 4.9.10: updated at least every 12 months
+			NextUpdateLimitSubscriber:   240 * time.Hour,  // BR 4.9.10: validity interval of at most 10 days
+			ClockSkewTolerance:          5 * time.Minute,
+			NextUpdateMinimumSubscriber: 8 * time.Hour,    // BR 4.9.10: validity interval of at least 8 hours
+			ResponderCertLifetimeLimit:  8760 * time.Hour, // the policy sets no limit, RFC 6960 asks for a short lifetime
+		},
+	},
+	"rfc5019": {
+		Name:        "rfc5019",
+		Description: "IETF RFC 5019 lightweight profile for high-volume environments, on top of RFC 6960",
+		LintIDs: []string{
+			"e_ocsp_signature_algorithm_does_not_match_key",
+			"e_ocsp_produced_at_in_future",
+			"e_ocsp_this_update_in_future",
+			"e_ocsp_this_update_after_produced_at",
+			"e_ocsp_responder_cert_missing_ocsp_signing_eku",
+			"e_ocsp_responder_cert_not_issued_by_issuer",
+			"e_ocsp_responder_id_does_not_match_signer",
+			"n_ocsp_certs_includes_issuer",
+			"n_ocsp_certs_includes_root",
+			"n_ocsp_certs_multiple",
+			"w_ocsp_certs_cert_did_not_sign",
+			"e_ocsp_cert_id_does_not_match_request",
+			"w_ocsp_single_response_not_requested",
+			"e_ocsp_nonce_does_not_match_request",
+			"w_ocsp_nonce_inconsistent_with_caching",
+			"e_ocsp_revoked_at_after_this_update",
+			"e_ocsp_revoked_at_after_produced_at",
+			"e_ocsp_revocation_reason_invalid",
+			"w_ocsp_responder_id_not_by_key",
+			"e_ocsp_response_extension_critical_unknown",
+			"e_ocsp_single_extension_critical_unknown",
+			"e_ocsp_nonce_invalid",
+			"e_ocsp_archive_cutoff_invalid",
+			"e_ocsp_crl_references_invalid",
+			"w_ocsp_response_extension_prevents_caching",
+			"e_ocsp_response_status_not_successful",
+			"e_ocsp_unsuccessful_response_has_response_bytes",
+			"e_ocsp_response_type_not_basic",
+			"e_ocsp_response_version_not_v1",
+			"e_ocsp_unexpected_fields",
+			"e_ocsp_der_default_version_encoded",
+			"e_ocsp_der_extension_critical_false_encoded",
+			"e_ocsp_der_generalized_time_not_canonical",
+		},
+		Thresholds: Thresholds{
+			// RFC 5019 sets no limits on how old a response may be, so the profile has no lints that check them
+			// and only the clock skew tolerance applies, which is more lenient for private PKIs
+			ClockSkewTolerance: time.Hour,
+		},
+	},
+	"rfc6960": {
+		Name:        "rfc6960",
+		Description: "IETF RFC 6960 only, for private PKIs not bound by WebPKI policies",
+		LintIDs: []string{
+			"e_ocsp_signature_algorithm_does_not_match_key",
+			"e_ocsp_produced_at_in_future",
+			"e_ocsp_this_update_in_future",
+			"e_ocsp_this_update_after_produced_at",
+			"e_ocsp_responder_cert_missing_ocsp_signing_eku",
+			"e_ocsp_responder_cert_not_issued_by_issuer",
+			"e_ocsp_responder_id_does_not_match_signer",
+			"w_ocsp_certs_cert_did_not_sign",
+			"e_ocsp_cert_id_does_not_match_request",
+			"w_ocsp_single_response_not_requested",
+			"e_ocsp_nonce_does_not_match_request",
+			"e_ocsp_revoked_at_after_this_update",
+			"e_ocsp_revoked_at_after_produced_at",
+			"e_ocsp_revocation_reason_invalid",
+			"e_ocsp_response_extension_critical_unknown",
+			"e_ocsp_single_extension_critical_unknown",
+			"e_ocsp_nonce_invalid",
+			"e_ocsp_archive_cutoff_invalid",
+			"e_ocsp_crl_references_invalid",
+			"e_ocsp_response_status_not_successful",
+			"e_ocsp_unsuccessful_response_has_response_bytes",
+			"e_ocsp_response_type_not_basic",
+			"e_ocsp_response_version_not_v1",
+			"e_ocsp_unexpected_fields",
+			"e_ocsp_der_default_version_encoded",
+			"e_ocsp_der_extension_critical_false_encoded",
+			"e_ocsp_der_generalized_time_not_canonical",
+		},
+		Thresholds: Thresholds{
+			// RFC 6960 sets no limits on how old a response may be, so the profile has no lints that check them
+			// and only the clock skew tolerance applies, which is more lenient for private PKIs
+			ClockSkewTolerance: time.Hour,
+		},
+	},
+}
+
+// GetProfile returns the profile with the given name
+func GetProfile(name string) (*Profile, error) {
+	profile, ok := Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("No profile named %s, must be one of %v", name, ProfileNames())
+	}
+
+	return profile, nil
+}
+
+// ProfileNames returns the sorted names of all profiles
+func ProfileNames() []string {
+	var names []string
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,113 @@
+package linter
+
+import (
+	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools"
+	"testing"
+	"time"
+)
+
+// TestProfiles tests that every profile only refers to registered lints
+func TestProfiles(t *testing.T) {
+	for _, name := range ProfileNames() {
+		profile, err := GetProfile(name)
+		if err != nil {
+			t.Fatalf("Got error getting profile %s: %s", name, err.Error())
+		}
+
+		lints, err := profile.Lints()
+		if err != nil {
+			t.Errorf("Got error getting lints of profile %s: %s", name, err.Error())
+		}
+
+		if len(lints) != len(profile.LintIDs) {
+			t.Errorf("Profile %s should have %d lints, instead has %d", name, len(profile.LintIDs), len(lints))
+		}
+	}
+
+	t.Run("Profile without lints", func(t *testing.T) {
+		_, err := (&Profile{Name: "empty"}).Lints()
+		if err == nil {
+			t.Errorf("Should have gotten error getting lints of profile without lints")
+		}
+	})
+
+	t.Run("Mozilla profile extends cabf profile", func(t *testing.T) {
+		cabf, mozilla := Profiles["cabf"], Profiles["mozilla"]
+		if len(mozilla.LintIDs) <= len(cabf.LintIDs) {
+			t.Errorf("Profile mozilla should have more lints than profile cabf")
+		}
+
+		mozillaLints := make(map[string]bool)
+		for _, id := range mozilla.LintIDs {
+			mozillaLints[id] = true
+		}
+		for _, id := range cabf.LintIDs {
+			if !mozillaLints[id] {
+				t.Errorf("Profile mozilla should have lint %s of profile cabf", id)
+			}
+		}
+	})
+
+	t.Run("Profiles have their own thresholds", func(t *testing.T) {
+		if Profiles["rfc6960"].Thresholds == Profiles["cabf"].Thresholds {
+			t.Errorf("Profile rfc6960 should not have the thresholds of profile cabf")
+		}
+	})
+
+	t.Run("Unknown profile", func(t *testing.T) {
+		_, err := GetProfile("blah")
+		if err == nil {
+			t.Errorf("Should have gotten error getting unknown profile")
+		}
+	})
+}
+
+// TestProfileThresholds tests that the linter's thresholds are used by the lints it runs
+func TestProfileThresholds(t *testing.T) {
+	ocspResp, _, err := ocsptools.Tools{}.ReadOCSPResp(RespBadDates)
+	if err != nil {
+		panic(fmt.Sprintf("Could not read OCSP Response file %s: %s", RespBadDates, err))
+	}
+
+	lint, err := GetLint("e_ocsp_produced_at_too_old")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	ocspResp.ProducedAt = time.Now().Add(-48 * time.Hour)
+
+	t.Run("Default thresholds", func(t *testing.T) {
+		report := Linter{Lints: []*LintStruct{lint}}.LintOCSPResp(&LintContext{Resp: ocspResp})
+		if report.Results[0].Status != Passed {
+			t.Errorf("Lint should have passed, instead got status %s: %s", report.Results[0].Status, report.Results[0].Info)
+		}
+	})
+
+	thresholds := DefaultThresholds
+	thresholds.ProducedAtLimitSubscriber = 24 * time.Hour
+
+	t.Run("Stricter thresholds", func(t *testing.T) {
+		report := Linter{Lints: []*LintStruct{lint}, Thresholds: &thresholds}.LintOCSPResp(&LintContext{Resp: ocspResp})
+		if report.Results[0].Status != Failed {
+			t.Errorf("Lint should have failed, instead got status %s: %s", report.Results[0].Status, report.Results[0].Info)
+		}
+	})
+}
+
+// TestDurationToString tests DurationToString, which converts durations to readable strings
+func TestDurationToString(t *testing.T) {
+	durations := map[time.Duration]string{
+		ProducedAtLimitSubscriber: "4 days",
+		24 * time.Hour:            "1 day",
+		8 * time.Hour:             "8 hours",
+		time.Hour:                 "1 hour",
+		90 * time.Minute:          "1h30m0s",
+	}
+
+	for d, expected := range durations {
+		if DurationToString(d) != expected {
+			t.Errorf("Duration %s should be %s, instead got %s", d, expected, DurationToString(d))
+		}
+	}
+}
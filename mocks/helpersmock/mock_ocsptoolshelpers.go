@@ -8,6 +8,7 @@ import (
 	crypto "crypto"
 	x509 "crypto/x509"
 	gomock "github.com/golang/mock/gomock"
+	helpers "github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
 	http "net/http"
 	reflect "reflect"
 )
@@ -51,12 +52,13 @@ func (mr *MockHelpersInterfaceMockRecorder) GetCertFromIssuerURL(arg0 interface{
 }
 
 // CreateOCSPReq mocks base method
-func (m *MockHelpersInterface) CreateOCSPReq(arg0 string, arg1, arg2 *x509.Certificate, arg3 string, arg4 crypto.Hash) (*http.Request, error) {
+func (m *MockHelpersInterface) CreateOCSPReq(arg0 string, arg1, arg2 *x509.Certificate, arg3 string, arg4 crypto.Hash) (*http.Request, []byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateOCSPReq", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(*http.Request)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // CreateOCSPReq indicates an expected call of CreateOCSPReq
@@ -66,10 +68,10 @@ func (mr *MockHelpersInterfaceMockRecorder) CreateOCSPReq(arg0, arg1, arg2, arg3
 }
 
 // GetOCSPResp mocks base method
-func (m *MockHelpersInterface) GetOCSPResp(arg0 *http.Request) ([]byte, error) {
+func (m *MockHelpersInterface) GetOCSPResp(arg0 *http.Request) (*helpers.HTTPResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOCSPResp", arg0)
-	ret0, _ := ret[0].([]byte)
+	ret0, _ := ret[0].(*helpers.HTTPResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
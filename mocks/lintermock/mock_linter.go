@@ -6,7 +6,7 @@ package lintermock
 
 import (
 	gomock "github.com/golang/mock/gomock"
-	ocsp "golang.org/x/crypto/ocsp"
+	linter "github.com/googleinterns/ocsp-response-linter/linter"
 	reflect "reflect"
 )
 
@@ -34,13 +34,15 @@ func (m *MockLinterInterface) EXPECT() *MockLinterInterfaceMockRecorder {
 }
 
 // LintOCSPResp mocks base method
-func (m *MockLinterInterface) LintOCSPResp(arg0 *ocsp.Response, arg1 bool) {
+func (m *MockLinterInterface) LintOCSPResp(arg0 *linter.LintContext) *linter.LintReport {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "LintOCSPResp", arg0, arg1)
+	ret := m.ctrl.Call(m, "LintOCSPResp", arg0)
+	ret0, _ := ret[0].(*linter.LintReport)
+	return ret0
 }
 
 // LintOCSPResp indicates an expected call of LintOCSPResp
-func (mr *MockLinterInterfaceMockRecorder) LintOCSPResp(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockLinterInterfaceMockRecorder) LintOCSPResp(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LintOCSPResp", reflect.TypeOf((*MockLinterInterface)(nil).LintOCSPResp), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LintOCSPResp", reflect.TypeOf((*MockLinterInterface)(nil).LintOCSPResp), arg0)
 }
@@ -8,6 +8,7 @@ import (
 	crypto "crypto"
 	x509 "crypto/x509"
 	gomock "github.com/golang/mock/gomock"
+	ocsptools "github.com/googleinterns/ocsp-response-linter/ocsptools"
 	helpers "github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
 	ocsp "golang.org/x/crypto/ocsp"
 	reflect "reflect"
@@ -37,12 +38,13 @@ func (m *MockToolsInterface) EXPECT() *MockToolsInterfaceMockRecorder {
 }
 
 // ReadOCSPResp mocks base method
-func (m *MockToolsInterface) ReadOCSPResp(arg0 string) (*ocsp.Response, error) {
+func (m *MockToolsInterface) ReadOCSPResp(arg0 string) (*ocsp.Response, []byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ReadOCSPResp", arg0)
 	ret0, _ := ret[0].(*ocsp.Response)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ReadOCSPResp indicates an expected call of ReadOCSPResp
@@ -82,10 +84,10 @@ func (mr *MockToolsInterfaceMockRecorder) GetIssuerCertFromLeafCert(arg0, arg1 i
 }
 
 // FetchOCSPResp mocks base method
-func (m *MockToolsInterface) FetchOCSPResp(arg0 helpers.HelpersInterface, arg1, arg2 string, arg3, arg4 *x509.Certificate, arg5 string, arg6 crypto.Hash) (*ocsp.Response, error) {
+func (m *MockToolsInterface) FetchOCSPResp(arg0 helpers.HelpersInterface, arg1, arg2 string, arg3, arg4 *x509.Certificate, arg5 string, arg6 crypto.Hash) (*ocsptools.FetchedResp, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FetchOCSPResp", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
-	ret0, _ := ret[0].(*ocsp.Response)
+	ret0, _ := ret[0].(*ocsptools.FetchedResp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
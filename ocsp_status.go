@@ -3,128 +3,307 @@ package main
 
 import (
 	"crypto"
+	"crypto/x509"
 	"flag"
 	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/config"
 	"github.com/googleinterns/ocsp-response-linter/linter"
 	"github.com/googleinterns/ocsp-response-linter/ocsptools"
 	"github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
+	"github.com/googleinterns/ocsp-response-linter/reporter"
 	"golang.org/x/crypto/ocsp"
+	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 )
 
-// checkFromFile takes a path to an OCSP Response file and then reads, parses, and lints it
-func checkFromFile(tools ocsptools.ToolsInterface, linter linter.LinterInterface, respFile string, issuerFile string, verbose bool) error {
-	ocspResp, err := tools.ReadOCSPResp(respFile)
-	if err != nil {
-		return err
+const (
+	ExitPassed     = 0      // all lints passed, or none at or above the -fail-on threshold did not pass
+	ExitLintFailed = 1      // at least one lint failed (or warned etc. depending on -fail-on)
+	ExitLintError  = 3      // at least one lint errored while running (2 is left for usage errors)
+	ExitCheckError = 4      // at least one OCSP response could not be fetched, read or parsed
+	FailOnNone     = "none" // -fail-on value for lint results to never result in a non-zero exit code
+)
+
+// parseFailOn takes the value of the -fail-on flag and returns the least severe lint status
+// that should result in a non-zero exit code, or Passed if lints should never do so
+func parseFailOn(failOn string) (linter.LintStatus, error) {
+	if strings.ToLower(failOn) == FailOnNone {
+		return linter.Passed, nil
 	}
 
-	issuerCert, err := tools.ParseCertificateFile(issuerFile)
+	status := linter.LintStatus(strings.ToUpper(failOn))
+	if _, ok := linter.StatusSeverity[status]; !ok || status.Passing() {
+		return "", fmt.Errorf("Unknown -fail-on threshold %s", failOn)
+	}
+
+	return status, nil
+}
+
+// parseAt parses the -at flag, returning the zero time if it is empty so that lints are evaluated at the current time
+func parseAt(at string) (time.Time, error) {
+	if at == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, at)
 	if err != nil {
-		return fmt.Errorf("Error parsing certificate from certificate file: %w", err)
+		return time.Time{}, fmt.Errorf("Error parsing -at time %s, must be in RFC 3339 format: %w", at, err)
 	}
 
-	linter.LintOCSPResp(ocspResp, issuerCert, verbose)
+	return parsed, nil
+}
+
+// exitCode returns the exit code for a lint report, only considering lint results
+// at least as severe as failOn, or with an unknown status
+func exitCode(report *linter.LintReport, failOn linter.LintStatus) int {
+	if failOn == linter.Passed {
+		return ExitPassed
+	}
 
-	return nil
+	code := ExitPassed
+	for _, result := range report.Results {
+		if severity, ok := linter.StatusSeverity[result.Status]; ok && severity < linter.StatusSeverity[failOn] {
+			continue
+		}
+
+		if result.Status == linter.Error {
+			return ExitLintError
+		}
+		code = ExitLintFailed
+	}
+
+	return code
 }
 
-// checkFromCert takes a path to an ASN.1 DER encoded certificate file and
-// constructs and sends an OCSP request then parses and lints the OCSP response
-func checkFromCert(tools ocsptools.ToolsInterface, linter linter.LinterInterface, certFile string, issuerFile string, isPost bool, ocspURL string, dir string, hash crypto.Hash, verbose bool) error {
+// splitList takes a comma separated list given as a flag and returns its elements
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	var elems []string
+	for _, elem := range strings.Split(list, ",") {
+		elems = append(elems, strings.TrimSpace(elem))
+	}
+
+	return elems
+}
+
+// targetsFromArgs takes the commandline arguments and flags describing the inputs to lint
+// and returns them as targets, pairing the idx-th argument with the idx-th issuer file and OCSP URL
+func targetsFromArgs(args []string, inresp bool, incert bool, issuerFiles []string, ocspURLs []string, isPost bool) []*config.Target {
+	targetType := config.TargetURL
+	if inresp {
+		targetType = config.TargetResp
+	} else if incert {
+		targetType = config.TargetCert
+	}
+
 	reqMethod := http.MethodGet
 	if isPost {
 		reqMethod = http.MethodPost
 	}
 
-	leafCert, err := tools.ParseCertificateFile(certFile)
+	var targets []*config.Target
+	for idx, arg := range args {
+		target := &config.Target{
+			Target: arg,
+			Type:   targetType,
+			Method: reqMethod,
+		}
+
+		if idx < len(issuerFiles) {
+			target.IssuerCert = issuerFiles[idx]
+		}
+
+		if idx < len(ocspURLs) {
+			target.OCSPURL = ocspURLs[idx]
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// checkFromFile takes a path to an OCSP Response file and then reads, parses, and lints it
+// issuerFile is the certificate of the issuer of the certificate the OCSP Response is for, if given
+func checkFromFile(tools ocsptools.ToolsInterface, lintr linter.LinterInterface, respFile string, issuerFile string) (*linter.LintReport, error) {
+	ocspResp, rawResp, err := tools.ReadOCSPResp(respFile)
 	if err != nil {
-		return fmt.Errorf("Error parsing certificate from certificate file: %w", err)
+		return nil, err
 	}
 
 	issuerCert, err := tools.ParseCertificateFile(issuerFile)
 	if err != nil {
-		return fmt.Errorf("Error parsing certificate from certificate file: %w", err)
+		return nil, fmt.Errorf("Error parsing certificate from certificate file: %w", err)
+	}
+
+	return lintr.LintOCSPResp(&linter.LintContext{
+		Resp:       ocspResp,
+		RawResp:    rawResp,
+		IssuerCert: issuerCert,
+		Transport:  linter.TransportFile,
+	}), nil
+}
+
+// checkFromCert takes a path to an ASN.1 DER encoded certificate file and
+// constructs and sends an OCSP request then parses and lints the OCSP response
+func checkFromCert(tools ocsptools.ToolsInterface, h helpers.HelpersInterface, lintr linter.LinterInterface, certFile string, issuerFile string, reqMethod string, ocspURL string, dir string, hash crypto.Hash) (*linter.LintReport, error) {
+	leafCert, err := tools.ParseCertificateFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing certificate from certificate file: %w", err)
 	}
 
-	h := helpers.Helpers{}
+	issuerCert, err := tools.ParseCertificateFile(issuerFile)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing certificate from certificate file: %w", err)
+	}
 
 	if issuerCert == nil {
 		issuerCert, err = tools.GetIssuerCertFromLeafCert(h, leafCert)
 		if err != nil {
-			return fmt.Errorf("Error getting issuer certificate from certificate: %w", err)
+			return nil, fmt.Errorf("Error getting issuer certificate from certificate: %w", err)
 		}
 	}
 
-	ocspResp, err := tools.FetchOCSPResp(h, ocspURL, dir, leafCert, issuerCert, reqMethod, hash)
+	fetched, err := tools.FetchOCSPResp(h, ocspURL, dir, leafCert, issuerCert, reqMethod, hash)
 	if err != nil {
-		return fmt.Errorf("Error fetching OCSP response: %w", err)
+		return nil, fmt.Errorf("Error fetching OCSP response: %w", err)
 	}
 
-	linter.LintOCSPResp(ocspResp, leafCert, verbose)
-
-	return nil
+	return lintr.LintOCSPResp(fetchedLintContext(fetched, leafCert, issuerCert)), nil
 }
 
 // checkFromURL takes a server URL and constructs and sends an OCSP request to
 // check that URL's certificate then parses and lints the OCSP response
-func checkFromURL(tools ocsptools.ToolsInterface, linter linter.LinterInterface, serverURL string, issuerFile string, shouldPrint bool, isPost bool, noStaple bool, ocspURL string, dir string, hash crypto.Hash, verbose bool) error {
+// If printOut is not nil, the certificate of the server is printed to it
+func checkFromURL(tools ocsptools.ToolsInterface, h helpers.HelpersInterface, lintr linter.LinterInterface, serverURL string, issuerFile string, printOut io.Writer, reqMethod string, noStaple bool, ocspURL string, dir string, hash crypto.Hash) (*linter.LintReport, error) {
 	certChain, ocspResp, err := tools.GetCertChainAndStapledResp(serverURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	leafCert := certChain[0] // the certificate we want to send to the CA
 
 	issuerCert, err := tools.ParseCertificateFile(issuerFile)
 	if err != nil {
-		return fmt.Errorf("Error parsing certificate from certificate file: %w", err)
+		return nil, fmt.Errorf("Error parsing certificate from certificate file: %w", err)
 	}
 
-	h := helpers.Helpers{}
-
 	if issuerCert == nil {
 		issuerCert, err = tools.GetIssuerCertFromLeafCert(h, leafCert)
 		if err != nil {
-			fmt.Println("Couldn't get issuer certificate from leaf certificate, taking the second certificate in the chain as the issuer certificate")
+			fmt.Fprintln(os.Stderr, "Couldn't get issuer certificate from leaf certificate, taking the second certificate in the chain as the issuer certificate")
 			issuerCert = certChain[1]
 		}
 	}
 
-	if shouldPrint {
-		err = ocsptools.PrintCert(leafCert)
+	if printOut != nil {
+		err = ocsptools.PrintCert(printOut, leafCert)
 		if err != nil {
-			return fmt.Errorf("Error printing certificate: %w", err)
+			return nil, fmt.Errorf("Error printing certificate: %w", err)
 		}
 	}
 
-	var parsedResp *ocsp.Response
-
 	if ocspResp == nil || noStaple {
-		reqMethod := http.MethodGet
-		if isPost {
-			reqMethod = http.MethodPost
+		fetched, err := tools.FetchOCSPResp(h, ocspURL, dir, leafCert, issuerCert, reqMethod, hash)
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching OCSP response: %w", err)
 		}
 
-		h := helpers.Helpers{}
+		return lintr.LintOCSPResp(fetchedLintContext(fetched, leafCert, issuerCert)), nil
+	}
 
-		parsedResp, err = tools.FetchOCSPResp(h, ocspURL, dir, leafCert, issuerCert, reqMethod, hash)
-		if err != nil {
-			return fmt.Errorf("Error fetching OCSP response: %w", err)
+	fmt.Fprintln(os.Stderr, "Stapled OCSP Response")
+
+	parsedResp, err := ocsptools.ParseOCSPResp(ocspResp, leafCert, issuerCert)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OCSP response: %w", err)
+	}
+
+	return lintr.LintOCSPResp(&linter.LintContext{
+		Resp:       parsedResp,
+		RawResp:    ocspResp,
+		LeafCert:   leafCert,
+		IssuerCert: issuerCert,
+		Transport:  linter.TransportStapled,
+	}), nil
+}
+
+// fetchedLintContext returns the lint context of an OCSP response fetched from an OCSP responder
+func fetchedLintContext(fetched *ocsptools.FetchedResp, leafCert *x509.Certificate, issuerCert *x509.Certificate) *linter.LintContext {
+	var httpMetadata *linter.HTTPMetadata
+	if fetched.HTTP != nil {
+		httpMetadata = &linter.HTTPMetadata{
+			StatusCode: fetched.HTTP.StatusCode,
+			Header:     fetched.HTTP.Header,
+			Latency:    fetched.HTTP.Latency,
 		}
-	} else {
-		fmt.Println("Stapled OCSP Response")
+	}
 
-		parsedResp, err = ocsp.ParseResponse(ocspResp, issuerCert)
-		if err != nil {
-			return fmt.Errorf("Error parsing OCSP response: %w", err)
+	return &linter.LintContext{
+		Resp:         fetched.Resp,
+		RawResp:      fetched.RawResp,
+		LeafCert:     leafCert,
+		IssuerCert:   issuerCert,
+		Request:      fetched.Request,
+		RawRequest:   fetched.RawRequest,
+		HTTP:         httpMetadata,
+		ResponderURL: fetched.ResponderURL,
+		Transport:    linter.TransportFetched,
+	}
+}
+
+// checkWithSHA1Fallback runs check with OCSP requests encoded with SHA256, retrying with SHA1 instead
+// if that fails, as some responders only support SHA1
+// An OCSP response whose status is not successful is not retried, so that its status is linted
+func checkWithSHA1Fallback(check func(hash crypto.Hash) (*linter.LintReport, error)) (*linter.LintReport, error) {
+	report, err := check(crypto.SHA256)
+	if err == nil {
+		if report.ResponseStatus != ocsp.Success {
+			fmt.Fprintf(os.Stderr, "OCSP Response status for OCSP Request encoded with SHA256 is %s \n\n",
+				linter.ResponseStatusString(report.ResponseStatus))
 		}
+		return report, nil
 	}
 
-	linter.LintOCSPResp(parsedResp, leafCert, verbose)
+	fmt.Fprintf(os.Stderr, "Validation failed for sending OCSP Request encoded with SHA256: %s \n\n", err.Error())
+
+	return check(crypto.SHA1)
+}
 
-	return nil
+// checkTarget checks a single target according to its type, retrying OCSP requests
+// encoded with SHA256 with SHA1 instead if they fail
+// If printOut is not nil, the certificates of server URL targets are printed to it
+func checkTarget(tools ocsptools.ToolsInterface, h helpers.HelpersInterface, lintr linter.LinterInterface, target *config.Target, printOut io.Writer, noStaple bool, dir string) (*linter.LintReport, error) {
+	switch target.Type {
+	case config.TargetResp:
+		report, err := checkFromFile(tools, lintr, target.Target, target.IssuerCert)
+		if err != nil {
+			return nil, fmt.Errorf("Error checking OCSP Response file %s: %w", target.Target, err)
+		}
+		return report, nil
+	case config.TargetCert:
+		report, err := checkWithSHA1Fallback(func(hash crypto.Hash) (*linter.LintReport, error) {
+			return checkFromCert(tools, h, lintr, target.Target, target.IssuerCert, target.Method, target.OCSPURL, dir, hash)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error checking certificate file %s: %w", target.Target, err)
+		}
+		return report, nil
+	default:
+		report, err := checkWithSHA1Fallback(func(hash crypto.Hash) (*linter.LintReport, error) {
+			return checkFromURL(tools, h, lintr, target.Target, target.IssuerCert, printOut, target.Method, noStaple, target.OCSPURL, dir, hash)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error checking server URL %s: %w", target.Target, err)
+		}
+		return report, nil
+	}
 }
 
 // main parses the users commandline arguments & flags and then runs the appropriate functions
@@ -139,16 +318,110 @@ func main() {
 	dir := flag.String("dir", "", "Where to write OCSP response")
 	noStaple := flag.Bool("nostaple", false, "Whether to send an OCSP request regardless of if there is a stapled OCSP response")
 	verbose := flag.Bool("verbose", false, "Whether to use verbose printing for printing lints")
+	format := flag.String("format", reporter.FormatText, "Output format for lint results, one of text, json, sarif or junit")
+	configFile := flag.String("config", "", "JSON configuration file describing targets, lints and thresholds, flags take precedence over it")
+	profileName := flag.String("profile", "", "Name of the lint profile to use, one of "+strings.Join(linter.ProfileNames(), ", ")+" (default all lints)")
+	includeLints := flag.String("include-lints", "", "Comma separated list of ids of the only lints to run")
+	excludeLints := flag.String("exclude-lints", "", "Comma separated list of ids of lints not to run")
+	includeSources := flag.String("include-sources", "", "Comma separated list of lint sources (e.g. Apple), only lints whose source contains one of them are run")
+	atFlag := flag.String("at", "", "RFC 3339 time (e.g. 2020-09-08T14:46:42Z) to evaluate time based lints at instead of the current time")
+	nonce := flag.Bool("nonce", false, "Whether to send a random nonce in OCSP requests and check that the responder echoes it")
+	failOnFlag := flag.String("fail-on", "failed", "Least severe lint status that results in a non-zero exit code, one of notice, warn, failed, error or none")
 
 	flag.Parse()
 
-	tools := ocsptools.Tools{}
-	linter := linter.Linter{}
-
 	if *inresp && *incert {
 		panic("This tool can only parse one file format at a time. Please use only one of -inresp or -incert.")
 	}
 
+	conf := &config.Config{}
+	if *configFile != "" {
+		var err error
+		conf, err = config.ReadConfig(*configFile)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+
+	// flags that are explicitly set take precedence over the configuration file
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	if setFlags["profile"] {
+		conf.Profile = *profileName
+	}
+	if setFlags["include-lints"] {
+		conf.IncludeLints = splitList(*includeLints)
+	}
+	if setFlags["exclude-lints"] {
+		conf.ExcludeLints = splitList(*excludeLints)
+	}
+	if setFlags["include-sources"] {
+		conf.IncludeSources = splitList(*includeSources)
+	}
+	if setFlags["nonce"] {
+		conf.Nonce = *nonce
+	}
+
+	lints := linter.Lints
+	thresholds := linter.DefaultThresholds
+	if conf.Profile != "" {
+		profile, err := linter.GetProfile(conf.Profile)
+		if err != nil {
+			panic(err.Error())
+		}
+
+		lints, err = profile.Lints()
+		if err != nil {
+			panic(err.Error())
+		}
+		thresholds = profile.Thresholds
+	}
+
+	lints, err := linter.FilterLints(lints, conf.IncludeLints, conf.ExcludeLints, conf.IncludeSources)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	thresholds, err = conf.ApplyThresholds(thresholds)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	at, err := parseAt(*atFlag)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	lintr := linter.Linter{
+		Lints:      lints,
+		Thresholds: &thresholds,
+		Now:        at,
+	}
+
+	tools := ocsptools.Tools{}
+
+	// errors were already checked when reading the configuration file
+	respTimeLimit, _ := conf.RespTimeLimitDuration()
+	timeout, _ := conf.TimeoutDuration()
+	h := helpers.Helpers{
+		RespTimeLimit: respTimeLimit,
+		Timeout:       timeout,
+		Nonce:         conf.Nonce,
+	}
+
+	rep, err := reporter.NewReporter(*format, os.Stdout, *verbose)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	failOn, err := parseFailOn(*failOnFlag)
+	if err != nil {
+		panic(err.Error())
+	}
+
 	var issuerFiles []string
 	if *issuerFile != "" {
 		issuerFiles = strings.Split(*issuerFile, " ")
@@ -159,48 +432,44 @@ func main() {
 		ocspURLs = strings.Split(*ocspurl, " ")
 	}
 
-	args := flag.Args()
-	for idx, arg := range args {
-		iFile := ""
-		if idx < len(issuerFiles) {
-			iFile = issuerFiles[idx]
+	targets := append(conf.Targets, targetsFromArgs(flag.Args(), *inresp, *incert, issuerFiles, ocspURLs, *isPost)...)
+
+	// the certificate text would corrupt machine readable output on stdout
+	var printOut io.Writer
+	if *shouldPrint {
+		printOut = os.Stdout
+		if *format != reporter.FormatText {
+			printOut = os.Stderr
 		}
+	}
 
-		ocspURL := ""
-		if idx < len(ocspURLs) {
-			ocspURL = ocspURLs[idx]
-		}
-
-		if *inresp {
-			// arg is a respFile
-			err := checkFromFile(tools, linter, arg, iFile, *verbose)
-			if err != nil {
-				fmt.Printf("Error checking OCSP Response file %s: %s \n\n", arg, err.Error())
-			}
-		} else if *incert {
-			// arg is a certFile
-			err := checkFromCert(tools, linter, arg, iFile, *isPost, ocspURL, *dir, crypto.SHA256, *verbose)
-			if err == nil {
-				continue
-			}
-			fmt.Printf("Validation failed for sending OCSP Request encoded with SHA256: %s \n\n", err.Error())
-
-			err = checkFromCert(tools, linter, arg, iFile, *isPost, ocspURL, *dir, crypto.SHA1, *verbose)
-			if err != nil {
-				fmt.Printf("Error checking certificate file %s: %s \n\n", arg, err.Error())
-			}
-		} else {
-			// arg is a serverURL
-			err := checkFromURL(tools, linter, arg, iFile, *shouldPrint, *isPost, *noStaple, ocspURL, *dir, crypto.SHA256, *verbose)
-			if err == nil {
-				continue
-			}
-			fmt.Printf("Validation failed for sending OCSP Request encoded with SHA256: %s \n\n", err.Error())
-
-			err = checkFromURL(tools, linter, arg, iFile, *shouldPrint, *isPost, *noStaple, ocspURL, *dir, crypto.SHA1, *verbose)
-			if err != nil {
-				fmt.Printf("Error checking server URL %s: %s \n\n", arg, err.Error())
-			}
+	// the exit code is the highest of the exit codes for every target
+	code := ExitPassed
+
+	for _, target := range targets {
+		report, err := checkTarget(tools, h, lintr, target, printOut, *noStaple, *dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s \n\n", err.Error())
+			code = ExitCheckError
+			continue
+		}
+
+		report.Target = target.Target
+		report.TargetURI = target.URI()
+		err = rep.Report(report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reporting lint results for %s: %s \n\n", target.Target, err.Error())
+		}
+
+		if reportCode := exitCode(report, failOn); reportCode > code {
+			code = reportCode
 		}
 	}
+
+	err = rep.Flush()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reporting lint results: %s \n\n", err.Error())
+	}
+
+	os.Exit(code)
 }
@@ -5,13 +5,19 @@ import (
 	"crypto/x509"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/googleinterns/ocsp-response-linter/config"
+	"github.com/googleinterns/ocsp-response-linter/linter"
 	"github.com/googleinterns/ocsp-response-linter/mocks/toolsmock"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
 	"github.com/googleinterns/ocsp-response-linter/testdata/resps"
 	"golang.org/x/crypto/ocsp"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"testing"
+	"time"
 )
 
 const (
@@ -27,9 +33,15 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-type MockLinter struct{}
+// MockLinter is a linter that records the context of the last OCSP response it linted
+type MockLinter struct {
+	ctx *linter.LintContext
+}
 
-func (ml MockLinter) LintOCSPResp(resp *ocsp.Response, leafCert *x509.Certificate, verbose bool) {}
+func (ml *MockLinter) LintOCSPResp(ctx *linter.LintContext) *linter.LintReport {
+	ml.ctx = ctx
+	return &linter.LintReport{}
+}
 
 // TestCheckFromFile tests checkFromFile, which reads an OCSP response file and lints it
 func TestCheckFromFile(t *testing.T) {
@@ -38,26 +50,30 @@ func TestCheckFromFile(t *testing.T) {
 	// mocking ocsptools.ReadOCSPResp
 	mt := toolsmock.NewMockToolsInterface(ctrl)
 	mt.EXPECT().ParseCertificateFile("").Return(nil, nil)
-	mt.EXPECT().ReadOCSPResp(Resp).Return(&ocsp.Response{}, nil)
+	mt.EXPECT().ReadOCSPResp(Resp).Return(&ocsp.Response{}, nil, nil)
 
 	// Alternate mocking scheme for linter, I want to keep this here just for memory
 	// When linting becomes more complicated, I may need to revert to doing this
 	// ml := toolsmock.NewMockLinterInterface(ctrl)
 	// ml.EXPECT().LintOCSPResp(gomock.AssignableToTypeOf(&ocsp.Response{})).Return()
 
-	ml := MockLinter{}
+	ml := &MockLinter{}
 
 	t.Run("Happy path", func(t *testing.T) {
-		err := checkFromFile(mt, ml, Resp, "", false)
+		_, err := checkFromFile(mt, ml, Resp, "")
 		if err != nil {
 			t.Errorf("Got error reading good response: %s", err.Error())
 		}
+
+		if ml.ctx.Transport != linter.TransportFile {
+			t.Errorf("OCSP response should have transport %s, instead has %s", linter.TransportFile, ml.ctx.Transport)
+		}
 	})
 
-	mt.EXPECT().ReadOCSPResp(Cert).Return(nil, fmt.Errorf(""))
+	mt.EXPECT().ReadOCSPResp(Cert).Return(nil, nil, fmt.Errorf(""))
 
 	t.Run("ReadOCSPResp errors", func(t *testing.T) {
-		err := checkFromFile(mt, ml, Cert, "", false)
+		_, err := checkFromFile(mt, ml, Cert, "")
 		if err == nil {
 			t.Errorf("Should have gotten error when ReadOCSPResp errors")
 		}
@@ -69,16 +85,16 @@ func TestCheckFromFile(t *testing.T) {
 func TestCheckFromCert(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	ml := MockLinter{}
+	ml := &MockLinter{}
 
 	mt := toolsmock.NewMockToolsInterface(ctrl)
 	mt.EXPECT().ParseCertificateFile(Cert).Return(&x509.Certificate{}, nil)
 	mt.EXPECT().ParseCertificateFile("").Return(nil, nil)
 	mt.EXPECT().GetIssuerCertFromLeafCert(gomock.Any(), gomock.Any()).Return(&x509.Certificate{}, nil)
-	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&ocsp.Response{}, nil)
+	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&ocsptools.FetchedResp{Resp: &ocsp.Response{}}, nil)
 
 	t.Run("Happy path", func(t *testing.T) {
-		err := checkFromCert(mt, ml, Cert, "", false, "", "", crypto.SHA1, false)
+		_, err := checkFromCert(mt, helpers.Helpers{}, ml, Cert, "", http.MethodGet, "", "", crypto.SHA1)
 		if err != nil {
 			t.Errorf("Got error reading good certificate file: %s", err.Error())
 		}
@@ -87,7 +103,7 @@ func TestCheckFromCert(t *testing.T) {
 	mt.EXPECT().ParseCertificateFile(Resp).Return(nil, fmt.Errorf(""))
 
 	t.Run("ParseCertificateFile errors", func(t *testing.T) {
-		err := checkFromCert(mt, ml, Resp, "", false, "", "", crypto.SHA1, false)
+		_, err := checkFromCert(mt, helpers.Helpers{}, ml, Resp, "", http.MethodGet, "", "", crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error when ParseCertificateFile errors")
 		}
@@ -98,7 +114,7 @@ func TestCheckFromCert(t *testing.T) {
 	mt.EXPECT().GetIssuerCertFromLeafCert(gomock.Any(), nil).Return(nil, fmt.Errorf(""))
 
 	t.Run("GetIssuerCertFromLeafCert errors", func(t *testing.T) {
-		err := checkFromCert(mt, ml, Cert, "", false, "", "", crypto.SHA1, false)
+		_, err := checkFromCert(mt, helpers.Helpers{}, ml, Cert, "", http.MethodGet, "", "", crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error when GetIssuerCertFromLeafCert errors")
 		}
@@ -110,7 +126,7 @@ func TestCheckFromCert(t *testing.T) {
 	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf(""))
 
 	t.Run("FetchOCSPResp errors", func(t *testing.T) {
-		err := checkFromCert(mt, ml, Cert, "", false, "", "", crypto.SHA1, false)
+		_, err := checkFromCert(mt, helpers.Helpers{}, ml, Cert, "", http.MethodGet, "", "", crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error when FetchOCSPResp errors")
 		}
@@ -125,19 +141,23 @@ func TestCheckFromURL(t *testing.T) {
 
 	mockChain := []*x509.Certificate{nil, nil}
 
-	ml := MockLinter{}
+	ml := &MockLinter{}
 
 	mt := toolsmock.NewMockToolsInterface(ctrl)
 	mt.EXPECT().GetCertChainAndStapledResp(gomock.Any()).Return(mockChain, nil, nil)
 	mt.EXPECT().GetIssuerCertFromLeafCert(gomock.Any(), gomock.Any()).Return(&x509.Certificate{}, nil)
 	mt.EXPECT().ParseCertificateFile("").Return(nil, nil)
-	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&ocsp.Response{}, nil)
+	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&ocsptools.FetchedResp{Resp: &ocsp.Response{}}, nil)
 
 	t.Run("Happy path", func(t *testing.T) {
-		err := checkFromURL(mt, ml, URL, "", false, false, false, "", "", crypto.SHA1, false)
+		_, err := checkFromURL(mt, helpers.Helpers{}, ml, URL, "", nil, http.MethodGet, false, "", "", crypto.SHA1)
 		if err != nil {
 			t.Errorf("Got error from good URL: %s", err.Error())
 		}
+
+		if ml.ctx.Transport != linter.TransportFetched {
+			t.Errorf("OCSP response should have transport %s, instead has %s", linter.TransportFetched, ml.ctx.Transport)
+		}
 	})
 
 	mt.EXPECT().GetCertChainAndStapledResp(gomock.Any()).Return(mockChain, resps.ByteArrayOCSPResp, nil)
@@ -145,10 +165,14 @@ func TestCheckFromURL(t *testing.T) {
 	mt.EXPECT().ParseCertificateFile("").Return(nil, nil)
 
 	t.Run("Happy path with stapled OCSP Response", func(t *testing.T) {
-		err := checkFromURL(mt, ml, URL, "", false, false, false, "", "", crypto.SHA1, false)
+		_, err := checkFromURL(mt, helpers.Helpers{}, ml, URL, "", nil, http.MethodGet, false, "", "", crypto.SHA1)
 		if err != nil {
 			t.Errorf("Got error with stapled OCSP Response: %s", err.Error())
 		}
+
+		if ml.ctx.Transport != linter.TransportStapled {
+			t.Errorf("OCSP response should have transport %s, instead has %s", linter.TransportStapled, ml.ctx.Transport)
+		}
 	})
 
 	mt.EXPECT().GetCertChainAndStapledResp(gomock.Any()).Return(mockChain, []byte{1}, nil)
@@ -156,7 +180,7 @@ func TestCheckFromURL(t *testing.T) {
 	mt.EXPECT().ParseCertificateFile("").Return(nil, nil)
 
 	t.Run("Bad byte array for OCSP Response", func(t *testing.T) {
-		err := checkFromURL(mt, ml, URL, "", false, false, false, "", "", crypto.SHA1, false)
+		_, err := checkFromURL(mt, helpers.Helpers{}, ml, URL, "", nil, http.MethodGet, false, "", "", crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error parsing bad byte array into OCSP response")
 		}
@@ -165,7 +189,7 @@ func TestCheckFromURL(t *testing.T) {
 	mt.EXPECT().GetCertChainAndStapledResp(gomock.Any()).Return(nil, nil, fmt.Errorf(""))
 
 	t.Run("GetCertChainAndStapledResp errors", func(t *testing.T) {
-		err := checkFromURL(mt, ml, URL, "", false, false, false, "", "", crypto.SHA1, false)
+		_, err := checkFromURL(mt, helpers.Helpers{}, ml, URL, "", nil, http.MethodGet, false, "", "", crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error when GetCertChainAndStapledResp errors")
 		}
@@ -177,9 +201,241 @@ func TestCheckFromURL(t *testing.T) {
 	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf(""))
 
 	t.Run("FetchOCSPResp errors", func(t *testing.T) {
-		err := checkFromURL(mt, ml, URL, "", false, false, false, "", "", crypto.SHA1, false)
+		_, err := checkFromURL(mt, helpers.Helpers{}, ml, URL, "", nil, http.MethodGet, false, "", "", crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error when FetchOCSPResp errors")
 		}
 	})
 }
+
+// TestParseFailOn tests parseFailOn, which parses the -fail-on flag
+func TestParseFailOn(t *testing.T) {
+	t.Run("Known thresholds", func(t *testing.T) {
+		thresholds := map[string]linter.LintStatus{
+			"warn":   linter.Warn,
+			"failed": linter.Failed,
+			"ERROR":  linter.Error,
+			"none":   linter.Passed,
+		}
+
+		for failOn, expected := range thresholds {
+			status, err := parseFailOn(failOn)
+			if err != nil || status != expected {
+				t.Errorf("Threshold %s should have parsed to %s, instead got %s: %v", failOn, expected, status, err)
+			}
+		}
+	})
+
+	t.Run("Unknown thresholds", func(t *testing.T) {
+		for _, failOn := range []string{"passed", "na", "blah"} {
+			_, err := parseFailOn(failOn)
+			if err == nil {
+				t.Errorf("Should have gotten error parsing threshold %s", failOn)
+			}
+		}
+	})
+}
+
+// TestParseAt tests parseAt, which parses the -at flag
+func TestParseAt(t *testing.T) {
+	t.Run("No time", func(t *testing.T) {
+		at, err := parseAt("")
+		if err != nil || !at.IsZero() {
+			t.Errorf("Empty time should have parsed to the zero time, instead got %s: %v", at, err)
+		}
+	})
+
+	t.Run("RFC 3339 time", func(t *testing.T) {
+		at, err := parseAt("2020-09-08T14:46:42Z")
+		if err != nil || !at.Equal(time.Date(2020, 9, 8, 14, 46, 42, 0, time.UTC)) {
+			t.Errorf("Time should have parsed to 2020-09-08 14:46:42 UTC, instead got %s: %v", at, err)
+		}
+	})
+
+	t.Run("Bad time", func(t *testing.T) {
+		_, err := parseAt("2020-09-08")
+		if err == nil {
+			t.Errorf("Should have gotten error parsing time that is not in RFC 3339 format")
+		}
+	})
+}
+
+// TestExitCode tests exitCode, which returns the exit code for a lint report
+func TestExitCode(t *testing.T) {
+	reportWith := func(statuses ...linter.LintStatus) *linter.LintReport {
+		report := &linter.LintReport{}
+		for _, status := range statuses {
+			report.Results = append(report.Results, &linter.LintResult{Status: status})
+		}
+		return report
+	}
+
+	tests := []struct {
+		name     string
+		report   *linter.LintReport
+		failOn   linter.LintStatus
+		expected int
+	}{
+		{"All passed", reportWith(linter.Passed, linter.Passed), linter.Failed, ExitPassed},
+		{"Lint failed", reportWith(linter.Passed, linter.Failed), linter.Failed, ExitLintFailed},
+		{"Lint errored", reportWith(linter.Error, linter.Failed), linter.Failed, ExitLintError},
+		{"Failed lint below threshold", reportWith(linter.Passed, linter.Failed), linter.Error, ExitPassed},
+		{"Errored lint at threshold", reportWith(linter.Failed, linter.Error), linter.Error, ExitLintError},
+		{"Never fail", reportWith(linter.Failed, linter.Error), linter.Passed, ExitPassed},
+		{"Warning below threshold", reportWith(linter.Warn, linter.NotApplicable), linter.Failed, ExitPassed},
+		{"Warning at threshold", reportWith(linter.Warn, linter.NotApplicable), linter.Warn, ExitLintFailed},
+		{"Not applicable below notice threshold", reportWith(linter.NotApplicable, linter.Passed), linter.Notice, ExitPassed},
+		{"Unknown status", reportWith(linter.Passed, linter.LintStatus("PASS")), linter.Error, ExitLintFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code := exitCode(test.report, test.failOn)
+			if code != test.expected {
+				t.Errorf("Exit code should have been %d, instead got %d", test.expected, code)
+			}
+		})
+	}
+}
+
+// TestFetchedLintContext tests fetchedLintContext, which returns the lint context of a fetched OCSP response
+func TestFetchedLintContext(t *testing.T) {
+	t.Run("HTTP metadata", func(t *testing.T) {
+		header := http.Header{"Cache-Control": []string{"no-cache"}}
+		ctx := fetchedLintContext(&ocsptools.FetchedResp{
+			HTTP: &helpers.HTTPResponse{Body: []byte{1}, StatusCode: http.StatusOK, Header: header, Latency: time.Second},
+		}, nil, nil)
+
+		if ctx.HTTP == nil || ctx.HTTP.StatusCode != http.StatusOK || ctx.HTTP.Header.Get("Cache-Control") != "no-cache" ||
+			ctx.HTTP.Latency != time.Second {
+			t.Errorf("Lint context should have the metadata of the http response, instead got %+v", ctx.HTTP)
+		}
+	})
+
+	t.Run("No http response", func(t *testing.T) {
+		if ctx := fetchedLintContext(&ocsptools.FetchedResp{}, nil, nil); ctx.HTTP != nil {
+			t.Errorf("Lint context should have no http metadata, instead got %+v", ctx.HTTP)
+		}
+	})
+}
+
+// TestSplitList tests splitList, which splits comma separated flags
+func TestSplitList(t *testing.T) {
+	if elems := splitList(""); elems != nil {
+		t.Errorf("Empty list should have no elements, instead got %v", elems)
+	}
+
+	elems := splitList("e_a, e_b,e_c")
+	if len(elems) != 3 || elems[0] != "e_a" || elems[1] != "e_b" || elems[2] != "e_c" {
+		t.Errorf("List was split incorrectly into %v", elems)
+	}
+}
+
+// TestTargetsFromArgs tests targetsFromArgs, which converts commandline arguments into targets
+func TestTargetsFromArgs(t *testing.T) {
+	targets := targetsFromArgs([]string{Cert, Cert}, false, true, []string{"issuer"}, nil, true)
+
+	if len(targets) != 2 {
+		t.Fatalf("Should have gotten 2 targets, instead got %d", len(targets))
+	}
+
+	if targets[0].Type != config.TargetCert || targets[0].Method != http.MethodPost {
+		t.Errorf("Target should be a certificate using POST, instead is a %s using %s", targets[0].Type, targets[0].Method)
+	}
+
+	if targets[0].IssuerCert != "issuer" || targets[1].IssuerCert != "" {
+		t.Errorf("Issuer certificates were not paired with arguments correctly")
+	}
+}
+
+// TestCheckTarget tests checkTarget, which checks a target according to its type
+// and retries with SHA1 if checking with SHA256 fails
+func TestCheckTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	ml := &MockLinter{}
+	h := helpers.Helpers{}
+
+	mt := toolsmock.NewMockToolsInterface(ctrl)
+
+	mt.EXPECT().ReadOCSPResp(Resp).Return(&ocsp.Response{}, nil, nil)
+	mt.EXPECT().ParseCertificateFile("").Return(nil, nil)
+
+	t.Run("OCSP response file", func(t *testing.T) {
+		_, err := checkTarget(mt, h, ml, &config.Target{Target: Resp, Type: config.TargetResp}, nil, false, "")
+		if err != nil {
+			t.Errorf("Got error checking OCSP response file: %s", err.Error())
+		}
+	})
+
+	mt.EXPECT().ParseCertificateFile(Cert).Return(&x509.Certificate{}, nil).Times(2)
+	mt.EXPECT().ParseCertificateFile("").Return(&x509.Certificate{}, nil).Times(2)
+	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), crypto.SHA256).Return(nil, fmt.Errorf(""))
+	mt.EXPECT().FetchOCSPResp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), crypto.SHA1).Return(&ocsptools.FetchedResp{Resp: &ocsp.Response{}}, nil)
+
+	t.Run("Certificate file retried with SHA1", func(t *testing.T) {
+		_, err := checkTarget(mt, h, ml, &config.Target{Target: Cert, Type: config.TargetCert}, nil, false, "")
+		if err != nil {
+			t.Errorf("Got error checking certificate file: %s", err.Error())
+		}
+	})
+
+	mt.EXPECT().GetCertChainAndStapledResp(URL).Return(nil, nil, fmt.Errorf("")).Times(2)
+
+	t.Run("Server URL errors", func(t *testing.T) {
+		_, err := checkTarget(mt, h, ml, &config.Target{Target: URL, Type: config.TargetURL}, nil, false, "")
+		if err == nil {
+			t.Errorf("Should have gotten error when checking server URL with SHA256 and SHA1 errors")
+		}
+	})
+}
+
+// TestCheckWithSHA1Fallback tests checkWithSHA1Fallback, which retries checking with SHA1
+// if checking with SHA256 fails or gets an OCSP response whose status is not successful
+func TestCheckWithSHA1Fallback(t *testing.T) {
+	successful := &linter.LintReport{ResponseStatus: ocsp.Success}
+	unauthorized := &linter.LintReport{ResponseStatus: ocsp.Unauthorized}
+
+	// check returns the result for the hash in results, recording the hashes it was called with
+	check := func(results map[crypto.Hash]*linter.LintReport, hashes *[]crypto.Hash) func(crypto.Hash) (*linter.LintReport, error) {
+		return func(hash crypto.Hash) (*linter.LintReport, error) {
+			*hashes = append(*hashes, hash)
+			if results[hash] == nil {
+				return nil, fmt.Errorf("")
+			}
+			return results[hash], nil
+		}
+	}
+
+	t.Run("Successful with SHA256", func(t *testing.T) {
+		var hashes []crypto.Hash
+		report, err := checkWithSHA1Fallback(check(map[crypto.Hash]*linter.LintReport{crypto.SHA256: successful}, &hashes))
+		if err != nil || report != successful || len(hashes) != 1 {
+			t.Errorf("Should have gotten the SHA256 report without retrying, instead retried %d times: %v", len(hashes)-1, err)
+		}
+	})
+
+	t.Run("Unsuccessful with SHA256", func(t *testing.T) {
+		var hashes []crypto.Hash
+		report, err := checkWithSHA1Fallback(check(map[crypto.Hash]*linter.LintReport{crypto.SHA256: unauthorized, crypto.SHA1: successful}, &hashes))
+		if err != nil || report != unauthorized || len(hashes) != 1 {
+			t.Errorf("Should have gotten the unsuccessful SHA256 report without retrying, instead retried %d times: %v", len(hashes)-1, err)
+		}
+	})
+
+	t.Run("Error with SHA256", func(t *testing.T) {
+		var hashes []crypto.Hash
+		report, err := checkWithSHA1Fallback(check(map[crypto.Hash]*linter.LintReport{crypto.SHA1: successful}, &hashes))
+		if err != nil || report != successful {
+			t.Errorf("Should have gotten the SHA1 report: %v", err)
+		}
+	})
+
+	t.Run("Both error", func(t *testing.T) {
+		var hashes []crypto.Hash
+		_, err := checkWithSHA1Fallback(check(map[crypto.Hash]*linter.LintReport{}, &hashes))
+		if err == nil {
+			t.Errorf("Should have gotten error when checking with SHA256 and SHA1 errors")
+		}
+	})
+}
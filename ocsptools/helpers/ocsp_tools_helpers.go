@@ -5,29 +5,69 @@ package helpers
 import (
 	"bytes"
 	"crypto"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
 	"fmt"
 	"golang.org/x/crypto/ocsp"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"time"
 )
 
 const (
-	RespTimeLimit    = "10s" // Time limit for OCSP response to be served
-	TimeoutInSeconds = 20    // Time limit for http response before timeout
+	RespTimeLimit    = "10s" // Default time limit for OCSP response to be served
+	TimeoutInSeconds = 20    // Default time limit for http response before timeout
+	NonceLength      = 32    // Length in bytes of the nonces sent in OCSP requests, the most RFC 8954 allows
 )
 
+// oidNonce is the OID of the id-pkix-ocsp-nonce extension (RFC 6960 section 4.4.1)
+var oidNonce = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+
 // HelpersInterface is an interface for the functions that can be used from this file
 type HelpersInterface interface {
 	GetCertFromIssuerURL(string) (*x509.Certificate, error)
-	CreateOCSPReq(string, *x509.Certificate, *x509.Certificate, string, crypto.Hash) (*http.Request, error)
-	GetOCSPResp(*http.Request) ([]byte, error)
+	CreateOCSPReq(string, *x509.Certificate, *x509.Certificate, string, crypto.Hash) (*http.Request, []byte, error)
+	GetOCSPResp(*http.Request) (*HTTPResponse, error)
+}
+
+// HTTPResponse defines the struct of an OCSP response received from an OCSP responder over HTTP
+type HTTPResponse struct {
+	Body       []byte        // body of the http response, which should be the DER encoded OCSP response
+	StatusCode int           // status code of the http response
+	Header     http.Header   // headers of the http response
+	Latency    time.Duration // time taken for the OCSP responder to respond
 }
 
 // Helpers is an exported struct of type HelpersInterface
-type Helpers struct{}
+type Helpers struct {
+	RespTimeLimit time.Duration // time limit for OCSP response to be served, RespTimeLimit if 0
+	Timeout       time.Duration // time limit for http response before timeout, TimeoutInSeconds if 0
+	Nonce         bool          // whether to send a random nonce in OCSP requests
+}
+
+// timeout returns the time limit for http responses before timeout
+func (h Helpers) timeout() time.Duration {
+	if h.Timeout == 0 {
+		return TimeoutInSeconds * time.Second
+	}
+	return h.Timeout
+}
+
+// respTimeLimit returns the time limit for the OCSP response to be served
+func (h Helpers) respTimeLimit() time.Duration {
+	if h.RespTimeLimit == 0 {
+		limit, err := time.ParseDuration(RespTimeLimit)
+		if err != nil {
+			panic(err.Error()) // error really shouldn't happen
+		}
+		return limit
+	}
+	return h.RespTimeLimit
+}
 
 // GetCertFromIssuerURL takes an issuerURL and sends a GET request to the URL to retrieve its certificate
 // Assumes that sending a GET request to the provided URL will return its certificate
@@ -38,7 +78,7 @@ func (h Helpers) GetCertFromIssuerURL(issuerURL string) (*x509.Certificate, erro
 	}
 
 	httpClient := &http.Client{
-		Timeout: TimeoutInSeconds * time.Second,
+		Timeout: h.timeout(),
 	}
 	resp, err := httpClient.Do(httpReq)
 	if err != nil {
@@ -65,12 +105,14 @@ func (h Helpers) GetCertFromIssuerURL(issuerURL string) (*x509.Certificate, erro
 // issuerCert is the certificate of the issuer of the leafCert
 // reqMethod is either GET or POST
 // hash is the hash to use to encode the request (either SHA1 or SHA256 right now)
-func (h Helpers) CreateOCSPReq(ocspURL string, leafCert *x509.Certificate, issuerCert *x509.Certificate, reqMethod string, hash crypto.Hash) (*http.Request, error) {
+// If h.Nonce is set, the request carries a random nonce of NonceLength bytes in its requestExtensions
+// It returns the DER encoded OCSP request along with the HTTP request that carries it
+func (h Helpers) CreateOCSPReq(ocspURL string, leafCert *x509.Certificate, issuerCert *x509.Certificate, reqMethod string, hash crypto.Hash) (*http.Request, []byte, error) {
 	if ocspURL == "" {
 		// leafCert probably is an intermediary
 		// may not be required to have an OCSP responder
 		if len(leafCert.OCSPServer) == 0 {
-			return nil, fmt.Errorf("Certificate does not have an OCSP server")
+			return nil, nil, fmt.Errorf("Certificate does not have an OCSP server")
 		}
 		ocspURL = leafCert.OCSPServer[0] // URL of OCSP Responder for this certificate
 	}
@@ -79,7 +121,20 @@ func (h Helpers) CreateOCSPReq(ocspURL string, leafCert *x509.Certificate, issue
 		Hash: hash,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("Failed creating OCSP Request: %w", err)
+		return nil, nil, fmt.Errorf("Failed creating OCSP Request: %w", err)
+	}
+
+	if h.Nonce {
+		nonce := make([]byte, NonceLength)
+		_, err = rand.Read(nonce)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed generating nonce: %w", err)
+		}
+
+		ocspReq, err = addNonce(ocspReq, nonce)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed adding nonce to OCSP Request: %w", err)
+		}
 	}
 
 	body := bytes.NewBuffer(ocspReq)
@@ -93,37 +148,81 @@ func (h Helpers) CreateOCSPReq(ocspURL string, leafCert *x509.Certificate, issue
 
 	httpReq, err := http.NewRequest(reqMethod, ocspURL, body)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create HTTP request: %w", err)
+		return nil, nil, fmt.Errorf("Failed to create HTTP request: %w", err)
 	}
 
 	httpReq.Header.Add("Content-Type", "application/ocsp-request")
 	httpReq.Header.Add("Accept", "application/ocsp-response")
 
-	return httpReq, nil
+	return httpReq, ocspReq, nil
+}
+
+// addNonce returns the DER encoded OCSP request ocspReq with a nonce extension added to its requestExtensions,
+// as ocsp.CreateRequest creates requests without any extensions
+func addNonce(ocspReq []byte, nonce []byte) ([]byte, error) {
+	var req asn1.RawValue
+	_, err := asn1.Unmarshal(ocspReq, &req)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OCSPRequest: %w", err)
+	}
+
+	// the optional signature after tbsRequest is kept as is
+	var tbs asn1.RawValue
+	signature, err := asn1.Unmarshal(req.Bytes, &tbs)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing TBSRequest: %w", err)
+	}
+
+	value, err := asn1.Marshal(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := asn1.Marshal(pkix.Extension{Id: oidNonce, Value: value})
+	if err != nil {
+		return nil, err
+	}
+
+	// requestExtensions is [2] EXPLICIT Extensions, the last field of TBSRequest
+	exts, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true, Bytes: ext})
+	if err != nil {
+		return nil, err
+	}
+
+	reqExts, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: exts})
+	if err != nil {
+		return nil, err
+	}
+
+	tbsDER, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true,
+		Bytes: append(append([]byte{}, tbs.Bytes...), reqExts...)})
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true,
+		Bytes: append(tbsDER, signature...)})
 }
 
 // GetOCSPResp takes an OCSP request in the form of an HTTP request sends it and returns the response
-// It also times the response time, and if it's over 10 seconds, then it has failed a verification
-func (h Helpers) GetOCSPResp(ocspReq *http.Request) ([]byte, error) {
+// It also times the response time, and if it's over the time limit, then it has failed a verification
+func (h Helpers) GetOCSPResp(ocspReq *http.Request) (*HTTPResponse, error) {
 	startTime := time.Now()
 
 	httpClient := &http.Client{
-		Timeout: TimeoutInSeconds * time.Second,
+		Timeout: h.timeout(),
 	}
 	httpResp, err := httpClient.Do(ocspReq)
 	if err != nil {
 		return nil, fmt.Errorf("Error sending http request: %w", err)
 	}
 
-	endTime := time.Now()
-	limit, err := time.ParseDuration(RespTimeLimit)
-	if err != nil {
-		panic(err.Error()) // error really shouldn't happen
-	}
+	latency := time.Since(startTime)
+	limit := h.respTimeLimit()
 
 	// Verification (source from Apple Lint 08)
-	if endTime.Sub(startTime) > limit {
-		fmt.Printf("Server took longer than %s to respond \n", RespTimeLimit)
+	if latency > limit {
+		fmt.Fprintf(os.Stderr, "Server took longer than %s to respond \n", limit)
 	}
 
 	defer httpResp.Body.Close()
@@ -133,5 +232,10 @@ func (h Helpers) GetOCSPResp(ocspReq *http.Request) ([]byte, error) {
 		return nil, fmt.Errorf("Error reading http response body: %w", err)
 	}
 
-	return ocspResp, nil
+	return &HTTPResponse{
+		Body:       ocspResp,
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Latency:    latency,
+	}, nil
 }
@@ -1,17 +1,24 @@
 package helpers
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"golang.org/x/crypto/ocsp"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const (
 	GoodCert       = "../../testdata/certs/google.der"       // good certificate
 	GoodIssuerCert = "../../testdata/certs/googleissuer.der" // issuer certificate for good certificate
 	URL            = "google.com:443"                        // sample URL
+	BadHost        = "127.0.0.1:0"                           // host that can't be connected to
 )
 
 // TestCreateOCSPReq tests CreateOCSPReq, which builds an OCSP request to check
@@ -27,14 +34,18 @@ func TestCreateOCSPReq(t *testing.T) {
 	issuerCert, _ := x509.ParseCertificate(icert)
 
 	t.Run("Happy path", func(t *testing.T) {
-		_, err := h.CreateOCSPReq("", leafCert, issuerCert, http.MethodGet, crypto.SHA1)
+		_, ocspReq, err := h.CreateOCSPReq("", leafCert, issuerCert, http.MethodGet, crypto.SHA1)
 		if err != nil {
 			t.Errorf("Got error with good parameters: %s", err.Error())
 		}
+
+		if len(ocspReq) == 0 {
+			t.Errorf("Should have gotten the DER encoded OCSP request")
+		}
 	})
 
 	t.Run("Specify OCSP URL and use POST", func(t *testing.T) {
-		httpReq, err := h.CreateOCSPReq(URL, leafCert, issuerCert, http.MethodPost, crypto.SHA1)
+		httpReq, _, err := h.CreateOCSPReq(URL, leafCert, issuerCert, http.MethodPost, crypto.SHA1)
 		if err != nil {
 			t.Errorf("Got error with good parameters: %s", err.Error())
 		}
@@ -49,8 +60,40 @@ func TestCreateOCSPReq(t *testing.T) {
 		}
 	})
 
+	t.Run("Nonce", func(t *testing.T) {
+		h := Helpers{Nonce: true}
+		_, ocspReq, err := h.CreateOCSPReq("", leafCert, issuerCert, http.MethodGet, crypto.SHA1)
+		if err != nil {
+			t.Fatalf("Got error with good parameters: %s", err.Error())
+		}
+
+		if _, err := ocsp.ParseRequest(ocspReq); err != nil {
+			t.Errorf("Got error parsing OCSP request with a nonce: %s", err.Error())
+		}
+
+		var req struct {
+			TBSRequest struct {
+				RequestList       asn1.RawValue
+				RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+			}
+		}
+		if _, err := asn1.Unmarshal(ocspReq, &req); err != nil {
+			t.Fatalf("Got error parsing OCSP request with a nonce: %s", err.Error())
+		}
+
+		exts := req.TBSRequest.RequestExtensions
+		if len(exts) != 1 || !exts[0].Id.Equal(oidNonce) {
+			t.Fatalf("OCSP request should have a nonce extension, instead has extensions %v", exts)
+		}
+
+		var nonce []byte
+		if _, err := asn1.Unmarshal(exts[0].Value, &nonce); err != nil || len(nonce) != NonceLength {
+			t.Errorf("OCSP request nonce should be an OCTET STRING of %d bytes, instead is %X", NonceLength, exts[0].Value)
+		}
+	})
+
 	t.Run("Bad issuer certificate", func(t *testing.T) {
-		_, err := h.CreateOCSPReq("", leafCert, &x509.Certificate{}, http.MethodGet, crypto.SHA1)
+		_, _, err := h.CreateOCSPReq("", leafCert, &x509.Certificate{}, http.MethodGet, crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error with bad issuer certificate")
 		}
@@ -58,9 +101,69 @@ func TestCreateOCSPReq(t *testing.T) {
 
 	leafCert.OCSPServer = nil
 	t.Run("Certificate without OCSP Server", func(t *testing.T) {
-		_, err := h.CreateOCSPReq("", leafCert, issuerCert, http.MethodGet, crypto.SHA1)
+		_, _, err := h.CreateOCSPReq("", leafCert, issuerCert, http.MethodGet, crypto.SHA1)
 		if err == nil {
 			t.Errorf("Should have gotten error with certificate with empty OCSPServer field")
 		}
 	})
 }
+
+// TestGetOCSPResp tests GetOCSPResp, which sends an OCSP request and returns
+// the OCSP response along with the metadata of the http response
+func TestGetOCSPResp(t *testing.T) {
+	h := Helpers{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write([]byte{1, 2, 3})
+	}))
+	defer server.Close()
+
+	t.Run("Happy path", func(t *testing.T) {
+		httpReq, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		httpResp, err := h.GetOCSPResp(httpReq)
+		if err != nil {
+			t.Fatalf("Got error getting OCSP response: %s", err.Error())
+		}
+
+		if !bytes.Equal(httpResp.Body, []byte{1, 2, 3}) {
+			t.Errorf("Got wrong http response body: %v", httpResp.Body)
+		}
+
+		if httpResp.StatusCode != http.StatusOK || httpResp.Header.Get("Content-Type") != "application/ocsp-response" {
+			t.Errorf("Did not get the status code and headers of the http response")
+		}
+	})
+
+	t.Run("Bad server", func(t *testing.T) {
+		httpReq, _ := http.NewRequest(http.MethodGet, "http://"+BadHost, nil)
+		_, err := h.GetOCSPResp(httpReq)
+		if err == nil {
+			t.Errorf("Should have gotten error sending request to bad server")
+		}
+	})
+}
+
+// TestHelpersTimeLimits tests that Helpers uses its configured time limits or falls back to the defaults
+func TestHelpersTimeLimits(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		h := Helpers{}
+		if h.timeout() != TimeoutInSeconds*time.Second {
+			t.Errorf("Timeout should default to %d seconds, instead got %s", TimeoutInSeconds, h.timeout())
+		}
+
+		if h.respTimeLimit().String() != RespTimeLimit {
+			t.Errorf("Response time limit should default to %s, instead got %s", RespTimeLimit, h.respTimeLimit())
+		}
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		h := Helpers{
+			RespTimeLimit: 5 * time.Second,
+			Timeout:       10 * time.Second,
+		}
+		if h.timeout() != 10*time.Second || h.respTimeLimit() != 5*time.Second {
+			t.Errorf("Configured time limits were not used")
+		}
+	})
+}
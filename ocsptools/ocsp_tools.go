@@ -6,47 +6,141 @@ import (
 	"crypto"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
 	"fmt"
 	"github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
 	"github.com/grantae/certinfo"
 	"golang.org/x/crypto/ocsp"
+	"io"
 	"io/ioutil"
+	"math/big"
 )
 
 // ToolsInterface is an interface for the functions that can be used from this file
 type ToolsInterface interface {
-	ReadOCSPResp(string) (*ocsp.Response, error)
+	ReadOCSPResp(string) (*ocsp.Response, []byte, error)
 	ParseCertificateFile(string) (*x509.Certificate, error)
 	GetIssuerCertFromLeafCert(helpers.HelpersInterface, *x509.Certificate) (*x509.Certificate, error)
-	FetchOCSPResp(helpers.HelpersInterface, string, string, *x509.Certificate, *x509.Certificate, string, crypto.Hash) (*ocsp.Response, error)
+	FetchOCSPResp(helpers.HelpersInterface, string, string, *x509.Certificate, *x509.Certificate, string, crypto.Hash) (*FetchedResp, error)
 	GetCertChainAndStapledResp(string) ([]*x509.Certificate, []byte, error)
 }
 
 // Tools is an exportable struct of type ToolsInterface
 type Tools struct{}
 
-// PrintCert prints the given certificate using the external library github.com/grantae/certinfo
-func PrintCert(cert *x509.Certificate) error {
+// FetchedResp defines the struct of an OCSP response fetched from an OCSP responder
+type FetchedResp struct {
+	Resp         *ocsp.Response        // the parsed OCSP response, nil if its status is not successful
+	RawResp      []byte                // the DER encoded OCSP response
+	Request      *ocsp.Request         // the OCSP request that was sent
+	RawRequest   []byte                // the DER encoded OCSP request that was sent
+	HTTP         *helpers.HTTPResponse // the http response the OCSP response was received in
+	ResponderURL string                // the URL of the OCSP responder the OCSP request was sent to
+}
+
+// PrintCert prints the given certificate to w using the external library github.com/grantae/certinfo
+func PrintCert(w io.Writer, cert *x509.Certificate) error {
 	result, err := certinfo.CertificateText(cert)
 	if err != nil {
 		return fmt.Errorf("failed converting certificate for printing: %w", err)
 	}
-	fmt.Print(result)
-	return nil
+	_, err = fmt.Fprint(w, result)
+	return err
+}
+
+// ParseOCSPResp parses an OCSP response, checking its signature if issuerCert is given
+// If the OCSP response has several SingleResponses, the one for leafCert is parsed, falling back to
+// the first one if leafCert is nil or has none, as ocsp.ParseResponse can only parse a single one
+// If the status of the OCSP response is not successful, it returns nil without an error,
+// as such a response has nothing to parse but is still a response that can be linted
+func ParseOCSPResp(ocspResp []byte, leafCert *x509.Certificate, issuerCert *x509.Certificate) (*ocsp.Response, error) {
+	// ocsp.ParseResponseForCert only uses the serial number of the certificate to pick the SingleResponse,
+	// if the serial numbers cannot be parsed it is left to report why
+	cert := leafCert
+	serials, err := singleResponseSerialNumbers(ocspResp)
+	if err == nil && len(serials) > 0 && (leafCert == nil || !containsSerialNumber(serials, leafCert.SerialNumber)) {
+		cert = &x509.Certificate{SerialNumber: serials[0]}
+	}
+
+	parsedResp, err := ocsp.ParseResponseForCert(ocspResp, cert, issuerCert)
+
+	var respErr ocsp.ResponseError
+	if errors.As(err, &respErr) {
+		return nil, nil
+	}
+
+	return parsedResp, err
+}
+
+// singleResponseSerialNumbers returns the serial numbers in the CertIDs of the SingleResponses of an OCSP response
+// Only the parts of the response leading up to them are parsed, the full structure is parsed by the linter
+func singleResponseSerialNumbers(ocspResp []byte) ([]*big.Int, error) {
+	var resp struct {
+		Status        asn1.Enumerated
+		ResponseBytes struct {
+			ResponseType asn1.ObjectIdentifier
+			Response     []byte
+		} `asn1:"explicit,tag:0"`
+	}
+	if _, err := asn1.Unmarshal(ocspResp, &resp); err != nil {
+		return nil, fmt.Errorf("Error parsing OCSPResponse: %w", err)
+	}
+
+	// trailing fields of each SEQUENCE are left unparsed
+	var basicResp struct {
+		TBSResponseData struct {
+			Version     int `asn1:"explicit,tag:0,default:0,optional"`
+			ResponderID asn1.RawValue
+			ProducedAt  asn1.RawValue
+			Responses   []struct {
+				CertID struct {
+					HashAlgorithm  pkix.AlgorithmIdentifier
+					IssuerNameHash []byte
+					IssuerKeyHash  []byte
+					SerialNumber   *big.Int
+				}
+			}
+		}
+	}
+	if _, err := asn1.Unmarshal(resp.ResponseBytes.Response, &basicResp); err != nil {
+		return nil, fmt.Errorf("Error parsing BasicOCSPResponse: %w", err)
+	}
+
+	var serials []*big.Int
+	for _, single := range basicResp.TBSResponseData.Responses {
+		serials = append(serials, single.CertID.SerialNumber)
+	}
+
+	return serials, nil
 }
 
-// ReadOCSPResp takes a path to an OCSP response file and reads and parses it
-func (t Tools) ReadOCSPResp(ocspRespFile string) (*ocsp.Response, error) {
+// containsSerialNumber returns whether serial is one of serials
+func containsSerialNumber(serials []*big.Int, serial *big.Int) bool {
+	for _, s := range serials {
+		if serial != nil && s.Cmp(serial) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadOCSPResp takes a path to an OCSP response file and reads and parses it,
+// returning both the parsed OCSP response and the bytes it was parsed from
+// The parsed OCSP response is nil if its status is not successful
+func (t Tools) ReadOCSPResp(ocspRespFile string) (*ocsp.Response, []byte, error) {
 	ocspResp, err := ioutil.ReadFile(ocspRespFile)
 	if err != nil {
-		return nil, fmt.Errorf("Error reading file: %w", err)
+		return nil, nil, fmt.Errorf("Error reading file: %w", err)
 	}
-	parsedResp, err := ocsp.ParseResponse(ocspResp, nil)
+	parsedResp, err := ParseOCSPResp(ocspResp, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing OCSP Response: %w", err)
+		return nil, nil, fmt.Errorf("Error parsing OCSP Response: %w", err)
 	}
 
-	return parsedResp, err
+	return parsedResp, ocspResp, err
 }
 
 // ParseCertificateFile takes a path to a certificate and returns a parsed certificate
@@ -86,32 +180,54 @@ func (t Tools) GetIssuerCertFromLeafCert(h helpers.HelpersInterface, leafCert *x
 }
 
 // FetchOCSPResp uses the functions above to create and send an OCSP Request
-// and then parse the returned OCSP response
+// and then parse the returned OCSP response, returning it along with the request
+// that was sent and the http response it was received in
+// If the status of the OCSP response is not successful, the parsed OCSP response is nil
 // If dir is specified, it will also write the OCSP Response to dir
-func (t Tools) FetchOCSPResp(h helpers.HelpersInterface, ocspURL string, dir string, leafCert *x509.Certificate, issuerCert *x509.Certificate, reqMethod string, hash crypto.Hash) (*ocsp.Response, error) {
-	ocspReq, err := h.CreateOCSPReq(ocspURL, leafCert, issuerCert, reqMethod, hash)
+func (t Tools) FetchOCSPResp(h helpers.HelpersInterface, ocspURL string, dir string, leafCert *x509.Certificate, issuerCert *x509.Certificate, reqMethod string, hash crypto.Hash) (*FetchedResp, error) {
+	httpReq, rawReq, err := h.CreateOCSPReq(ocspURL, leafCert, issuerCert, reqMethod, hash)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating OCSP Request: %w", err)
 	}
 
-	ocspResp, err := h.GetOCSPResp(ocspReq)
+	ocspReq, err := ocsp.ParseRequest(rawReq)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OCSP Request: %w", err)
+	}
+
+	httpResp, err := h.GetOCSPResp(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("Error getting OCSP Response: %w", err)
 	}
 
+	ocspResp := httpResp.Body
+
 	if dir != "" {
 		err := ioutil.WriteFile(dir, ocspResp, 0644)
 		if err != nil {
 			return nil, fmt.Errorf("Error writing OCSP Response to file %s: %w", dir, err)
 		}
 	}
-	// note that ocsp.ParseResponse also checks ocspResp's signature
-	parsedResp, err := ocsp.ParseResponse(ocspResp, issuerCert)
+	// note that ParseOCSPResp also checks ocspResp's signature
+	parsedResp, err := ParseOCSPResp(ocspResp, leafCert, issuerCert)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing OCSP response: %w", err)
 	}
 
-	return parsedResp, nil
+	// CreateOCSPReq sends the request to the first OCSP server of the certificate if no URL is given
+	responderURL := ocspURL
+	if responderURL == "" && leafCert != nil && len(leafCert.OCSPServer) > 0 {
+		responderURL = leafCert.OCSPServer[0]
+	}
+
+	return &FetchedResp{
+		Resp:         parsedResp,
+		RawResp:      ocspResp,
+		Request:      ocspReq,
+		RawRequest:   rawReq,
+		HTTP:         httpResp,
+		ResponderURL: responderURL,
+	}, nil
 }
 
 // GetCertChainAndStapledResp takes in a serverURL, attempts to build a tls connection to it
@@ -1,24 +1,31 @@
 package ocsptools
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/x509"
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/googleinterns/ocsp-response-linter/linter"
 	"github.com/googleinterns/ocsp-response-linter/mocks/helpersmock"
+	"github.com/googleinterns/ocsp-response-linter/ocsptools/helpers"
 	"github.com/googleinterns/ocsp-response-linter/testdata/resps"
 	"golang.org/x/crypto/ocsp"
+	"io/ioutil"
+	"math/big"
+	"strings"
 	"testing"
 )
 
 const (
-	GoodResp        = "../testdata/resps/oldfbresp"        // good response
-	GoodCert        = "../testdata/certs/google.der"       // good certificate
-	GoodIssuerCert  = "../testdata/certs/googleissuer.der" // issuer certificate for good certificate
-	NoIssuerURLCert = "../testdata/certs/rootcert.der"     // certificate with no issuer URL field
-	BadPath         = "blah///blah/blah.blah"              // bad file path
-	URL             = "google.com:443"                     // sample URL
+	GoodResp         = "../testdata/resps/oldfbresp"        // good response
+	UnauthorizedResp = "../testdata/resps/unauthorizedresp" // response with status unauthorized
+	MultipleResp     = "../testdata/resps/multiresp"        // response with SingleResponses for serial numbers 1 and 2
+	GoodCert         = "../testdata/certs/google.der"       // good certificate
+	GoodIssuerCert   = "../testdata/certs/googleissuer.der" // issuer certificate for good certificate
+	NoIssuerURLCert  = "../testdata/certs/rootcert.der"     // certificate with no issuer URL field
+	BadPath          = "blah///blah/blah.blah"              // bad file path
+	URL              = "google.com:443"                     // sample URL
 )
 
 // TestReadOCSPResp tests ReadOCSPResp, which reads and parses an OCSP response file
@@ -26,11 +33,15 @@ func TestReadOCSPResp(t *testing.T) {
 	tools := Tools{}
 
 	t.Run("Happy path", func(t *testing.T) {
-		parsedResp, err := tools.ReadOCSPResp(GoodResp)
+		parsedResp, rawResp, err := tools.ReadOCSPResp(GoodResp)
 		if err != nil {
 			t.Errorf("Got error reading good response: %s", err.Error())
 		}
 
+		if len(rawResp) == 0 {
+			t.Errorf("Should have gotten the bytes of the OCSP Response file")
+		}
+
 		// check if OCSP Response status was parsed correctly
 		status := parsedResp.Status
 		if status != ocsp.Good {
@@ -39,15 +50,38 @@ func TestReadOCSPResp(t *testing.T) {
 		}
 	})
 
+	t.Run("Unsuccessful response", func(t *testing.T) {
+		parsedResp, rawResp, err := tools.ReadOCSPResp(UnauthorizedResp)
+		if err != nil {
+			t.Errorf("Got error reading unsuccessful response: %s", err.Error())
+		}
+
+		if parsedResp != nil || len(rawResp) == 0 {
+			t.Errorf("Should have gotten only the bytes of the unsuccessful OCSP Response file")
+		}
+	})
+
+	t.Run("Response with several SingleResponses", func(t *testing.T) {
+		parsedResp, _, err := tools.ReadOCSPResp(MultipleResp)
+		if err != nil {
+			t.Fatalf("Got error reading response with several SingleResponses: %s", err.Error())
+		}
+
+		if parsedResp.SerialNumber.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("Should have parsed the first SingleResponse, instead parsed the one for serial number %s",
+				parsedResp.SerialNumber)
+		}
+	})
+
 	t.Run("Bad file path", func(t *testing.T) {
-		_, err := tools.ReadOCSPResp(BadPath)
+		_, _, err := tools.ReadOCSPResp(BadPath)
 		if err == nil {
 			t.Errorf("Should have gotten error reading bad file path")
 		}
 	})
 
 	t.Run("Reading file that is not OCSP Response", func(t *testing.T) {
-		_, err := tools.ReadOCSPResp(GoodCert)
+		_, _, err := tools.ReadOCSPResp(GoodCert)
 		if err == nil {
 			t.Errorf("Should have gotten error reading file that is not an OCSP response")
 		}
@@ -88,6 +122,24 @@ func TestParseCertificateFile(t *testing.T) {
 	})
 }
 
+// TestPrintCert tests PrintCert, which prints a certificate to a writer
+func TestPrintCert(t *testing.T) {
+	cert, err := Tools{}.ParseCertificateFile(GoodCert)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var out bytes.Buffer
+	err = PrintCert(&out, cert)
+	if err != nil {
+		t.Errorf("Got error printing good certificate: %s", err.Error())
+	}
+
+	if !strings.Contains(out.String(), cert.Subject.CommonName) {
+		t.Errorf("Printed certificate should contain its common name %s", cert.Subject.CommonName)
+	}
+}
+
 // TestGetIssuerCertFromLeafCert tests GetIssuerCertFromLeafCert, which checks for the
 // IssuingCertificateURL field in the given leaf certificate, and if it's present,
 // sends a GET request to that URL and parses the response into a certificate
@@ -130,20 +182,56 @@ func TestGetIssuerCertFromLeafCert(t *testing.T) {
 func TestFetchOCSPResp(t *testing.T) {
 	tools := Tools{}
 
+	leafCert, err := tools.ParseCertificateFile(GoodCert)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	issuerCert, err := tools.ParseCertificateFile(GoodIssuerCert)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	rawReq, err := ocsp.CreateRequest(leafCert, issuerCert, nil)
+	if err != nil {
+		panic(err.Error())
+	}
+
 	ctrl := gomock.NewController(t)
 
 	h := helpersmock.NewMockHelpersInterface(ctrl)
 
-	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	h.EXPECT().GetOCSPResp(gomock.Any()).Return(resps.ByteArrayOCSPResp, nil)
+	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rawReq, nil)
+	h.EXPECT().GetOCSPResp(gomock.Any()).Return(&helpers.HTTPResponse{Body: resps.ByteArrayOCSPResp}, nil)
 	t.Run("Happy path", func(t *testing.T) {
-		_, err := tools.FetchOCSPResp(h, "", "", nil, nil, "", crypto.SHA1)
+		fetched, err := tools.FetchOCSPResp(h, "", "", nil, nil, "", crypto.SHA1)
 		if err != nil {
-			t.Errorf("Got error fetching OCSP response with good parameters: %s", err.Error())
+			t.Fatalf("Got error fetching OCSP response with good parameters: %s", err.Error())
+		}
+
+		if fetched.Request == nil || fetched.Request.SerialNumber.Cmp(leafCert.SerialNumber) != 0 {
+			t.Errorf("Should have gotten the OCSP request that was sent")
 		}
 	})
 
-	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf(""))
+	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rawReq, nil)
+	h.EXPECT().GetOCSPResp(gomock.Any()).Return(&helpers.HTTPResponse{Body: []byte{0x30, 0x03, 0x0a, 0x01, 0x06}}, nil)
+	t.Run("Unsuccessful response", func(t *testing.T) {
+		fetched, err := tools.FetchOCSPResp(h, "", "", leafCert, nil, "", crypto.SHA1)
+		if err != nil {
+			t.Fatalf("Got error fetching unsuccessful OCSP response: %s", err.Error())
+		}
+
+		if fetched.Resp != nil || len(fetched.RawResp) == 0 {
+			t.Errorf("Should have gotten only the bytes of the unsuccessful OCSP response")
+		}
+
+		if fetched.ResponderURL != leafCert.OCSPServer[0] {
+			t.Errorf("Should have gotten the OCSP server of the certificate as responder URL, instead got %s", fetched.ResponderURL)
+		}
+	})
+
+	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, fmt.Errorf(""))
 	t.Run("CreateOCSPReq errors", func(t *testing.T) {
 		_, err := tools.FetchOCSPResp(h, "", "", nil, nil, "", crypto.SHA1)
 		if err == nil {
@@ -151,7 +239,7 @@ func TestFetchOCSPResp(t *testing.T) {
 		}
 	})
 
-	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rawReq, nil)
 	h.EXPECT().GetOCSPResp(gomock.Any()).Return(nil, fmt.Errorf(""))
 	t.Run("GetOCSPResp errors", func(t *testing.T) {
 		_, err := tools.FetchOCSPResp(h, "", "", nil, nil, "", crypto.SHA1)
@@ -160,8 +248,8 @@ func TestFetchOCSPResp(t *testing.T) {
 		}
 	})
 
-	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	h.EXPECT().GetOCSPResp(gomock.Any()).Return(resps.ByteArrayOCSPResp, nil)
+	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rawReq, nil)
+	h.EXPECT().GetOCSPResp(gomock.Any()).Return(&helpers.HTTPResponse{Body: resps.ByteArrayOCSPResp}, nil)
 	t.Run("Bad directory", func(t *testing.T) {
 		_, err := tools.FetchOCSPResp(h, "", BadPath, nil, nil, "", crypto.SHA1)
 		if err == nil {
@@ -169,8 +257,8 @@ func TestFetchOCSPResp(t *testing.T) {
 		}
 	})
 
-	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	h.EXPECT().GetOCSPResp(gomock.Any()).Return([]byte{1}, nil)
+	h.EXPECT().CreateOCSPReq(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, rawReq, nil)
+	h.EXPECT().GetOCSPResp(gomock.Any()).Return(&helpers.HTTPResponse{Body: []byte{1}}, nil)
 	t.Run("Bad OCSP Response", func(t *testing.T) {
 		_, err := tools.FetchOCSPResp(h, "", "", nil, nil, "", crypto.SHA1)
 		if err == nil {
@@ -178,3 +266,67 @@ func TestFetchOCSPResp(t *testing.T) {
 		}
 	})
 }
+
+// TestParseOCSPResp tests ParseOCSPResp, which parses the SingleResponse of an OCSP response for a certificate
+func TestParseOCSPResp(t *testing.T) {
+	ocspResp, err := ioutil.ReadFile(MultipleResp)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	t.Run("SingleResponse for the certificate", func(t *testing.T) {
+		parsedResp, err := ParseOCSPResp(ocspResp, &x509.Certificate{SerialNumber: big.NewInt(2)}, nil)
+		if err != nil {
+			t.Fatalf("Got error parsing response: %s", err.Error())
+		}
+
+		if parsedResp.SerialNumber.Cmp(big.NewInt(2)) != 0 {
+			t.Errorf("Should have parsed the SingleResponse for serial number 2, instead parsed the one for serial number %s",
+				parsedResp.SerialNumber)
+		}
+	})
+
+	t.Run("No SingleResponse for the certificate", func(t *testing.T) {
+		parsedResp, err := ParseOCSPResp(ocspResp, &x509.Certificate{SerialNumber: big.NewInt(3)}, nil)
+		if err != nil {
+			t.Fatalf("Got error parsing response: %s", err.Error())
+		}
+
+		if parsedResp.SerialNumber.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("Should have parsed the first SingleResponse, instead parsed the one for serial number %s",
+				parsedResp.SerialNumber)
+		}
+	})
+
+	t.Run("Unsuccessful response", func(t *testing.T) {
+		parsedResp, err := ParseOCSPResp([]byte{0x30, 0x03, 0x0a, 0x01, 0x06}, nil, nil)
+		if err != nil || parsedResp != nil {
+			t.Errorf("Should have gotten no response and no error, instead got error: %v", err)
+		}
+	})
+}
+
+// TestSingleResponseSerialNumbers tests singleResponseSerialNumbers, which returns the serial numbers
+// of the SingleResponses of an OCSP response
+func TestSingleResponseSerialNumbers(t *testing.T) {
+	ocspResp, err := ioutil.ReadFile(MultipleResp)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	serials, err := singleResponseSerialNumbers(ocspResp)
+	if err != nil {
+		t.Fatalf("Got error parsing serial numbers: %s", err.Error())
+	}
+
+	if len(serials) != 2 || serials[0].Int64() != 1 || serials[1].Int64() != 2 {
+		t.Errorf("Should have gotten serial numbers 1 and 2, instead got %v", serials)
+	}
+
+	t.Run("Not an OCSP response", func(t *testing.T) {
+		_, err := singleResponseSerialNumbers([]byte{1})
+		if err == nil {
+			t.Errorf("Should have gotten error parsing serial numbers of bad response")
+		}
+	})
+}
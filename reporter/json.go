@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"encoding/json"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"io"
+	"time"
+)
+
+// JSONResult defines the JSON form of the result of a lint
+type JSONResult struct {
+	ID             string            `json:"id"`                        // stable identifier of the lint
+	Lint           string            `json:"lint"`                      // description of the lint
+	Source         string            `json:"source"`                    // source of the lint
+	Status         linter.LintStatus `json:"status"`                    // status the lint returned
+	Message        string            `json:"message"`                   // additional information on the status
+	SingleResponse *int              `json:"single_response,omitempty"` // index of the other SingleResponse the result is for
+	SerialNumber   string            `json:"serial_number,omitempty"`   // serial number of the other SingleResponse the result is for
+}
+
+// JSONReport defines the JSON document output for every linted OCSP response
+type JSONReport struct {
+	Target         string        `json:"target"`
+	ResponseStatus string        `json:"response_status"`
+	SerialNumber   string        `json:"serial_number,omitempty"`
+	StartTime      time.Time     `json:"start_time"`
+	EvaluatedAt    time.Time     `json:"evaluated_at"`
+	Duration       time.Duration `json:"duration_ns"`
+	Results        []*JSONResult `json:"results"`
+}
+
+// JSONReporter is a struct of type ReporterInterface that writes each lint report
+// as a single line JSON document
+type JSONReporter struct {
+	Out io.Writer // where to write the reports
+}
+
+// NewJSONReport converts a lint report into its JSON form
+func NewJSONReport(report *linter.LintReport) *JSONReport {
+	jsonReport := &JSONReport{
+		Target:         report.Target,
+		ResponseStatus: report.Status(),
+		StartTime:      report.StartTime,
+		EvaluatedAt:    report.EvaluatedAt,
+		Duration:       report.Duration,
+		Results:        []*JSONResult{},
+	}
+
+	if report.SerialNumber != nil {
+		jsonReport.SerialNumber = report.SerialNumber.String()
+	}
+
+	for _, result := range report.Results {
+		jsonResult := &JSONResult{
+			ID:      result.Lint.ID,
+			Lint:    result.Lint.Info,
+			Source:  result.Lint.Source,
+			Status:  result.Status,
+			Message: result.Info,
+		}
+
+		// results for SingleResponses other than the one of the report say which one they are for
+		if result.SerialNumber != nil {
+			singleResponse := result.SingleResponse
+			jsonResult.SingleResponse = &singleResponse
+			jsonResult.SerialNumber = result.SerialNumber.String()
+		}
+
+		jsonReport.Results = append(jsonReport.Results, jsonResult)
+	}
+
+	return jsonReport
+}
+
+// Report writes the lint report as a JSON document followed by a newline
+func (r JSONReporter) Report(report *linter.LintReport) error {
+	enc := json.NewEncoder(r.Out)
+	enc.SetEscapeHTML(false) // lint sources contain characters such as &
+	return enc.Encode(NewJSONReport(report))
+}
+
+// Flush does nothing as JSONReporter writes each report as it is given
+func (r JSONReporter) Flush() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// TestJSONReporter tests JSONReporter, which writes a JSON document for every lint report
+func TestJSONReporter(t *testing.T) {
+	report := sampleReport()
+	report.SerialNumber = big.NewInt(1234)
+	report.Results = append(report.Results, &linter.LintResult{
+		Lint:           linter.Lints[0],
+		Status:         linter.Passed,
+		Info:           "other single response info",
+		SingleResponse: 0,
+		SerialNumber:   big.NewInt(2),
+	})
+
+	var out bytes.Buffer
+	r := JSONReporter{Out: &out}
+
+	for i := 0; i < 2; i++ {
+		err := r.Report(report)
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Should have written one line per report, instead got: %s", out.String())
+	}
+
+	var jsonReport JSONReport
+	err := json.Unmarshal([]byte(lines[0]), &jsonReport)
+	if err != nil {
+		t.Fatalf("Could not unmarshal JSON report: %s", err.Error())
+	}
+
+	t.Run("Report metadata", func(t *testing.T) {
+		if jsonReport.Target != report.Target {
+			t.Errorf("JSON report should have target %s, instead has %s", report.Target, jsonReport.Target)
+		}
+
+		if jsonReport.ResponseStatus != "good" {
+			t.Errorf("JSON report should have response status good, instead has %s", jsonReport.ResponseStatus)
+		}
+
+		if jsonReport.SerialNumber != "1234" {
+			t.Errorf("JSON report should have serial number 1234, instead has %s", jsonReport.SerialNumber)
+		}
+	})
+
+	t.Run("Report results", func(t *testing.T) {
+		if len(jsonReport.Results) != len(report.Results) {
+			t.Fatalf("JSON report should have %d results, instead has %d", len(report.Results), len(jsonReport.Results))
+		}
+
+		result := jsonReport.Results[1]
+		if result.ID != linter.Lints[1].ID || result.Lint != linter.Lints[1].Info || result.Source != linter.Lints[1].Source {
+			t.Errorf("JSON result has wrong lint %s from %s", result.Lint, result.Source)
+		}
+
+		if result.Status != linter.Failed || result.Message != "failed info" {
+			t.Errorf("JSON result has wrong status %s: %s", result.Status, result.Message)
+		}
+
+		if result.SingleResponse != nil || result.SerialNumber != "" {
+			t.Errorf("JSON result for the SingleResponse of the report should not name a SingleResponse")
+		}
+	})
+
+	t.Run("Results for other SingleResponses", func(t *testing.T) {
+		result := jsonReport.Results[2]
+		if result.SingleResponse == nil || *result.SingleResponse != 0 || result.SerialNumber != "2" {
+			t.Errorf("JSON result should be for SingleResponse 0 with serial number 2, instead got: %s", lines[0])
+		}
+
+		if strings.Contains(lines[0], `"serial_number":""`) || strings.Count(lines[0], `"single_response"`) != 1 {
+			t.Errorf("Only results for other SingleResponses should have SingleResponse fields, instead got: %s", lines[0])
+		}
+	})
+}
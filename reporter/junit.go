@@ -0,0 +1,142 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"io"
+	"time"
+)
+
+// The following structs are the commonly used subset of the JUnit XML format,
+// with every target being a test suite and every lint being a test case
+
+// JUnitTestSuites is the top level JUnit XML element
+type JUnitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite describes the lints run on a single target
+type JUnitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Cases     []*JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase describes the result of a single lint
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitSkipped describes why a lint did not apply
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnitFailure describes why a lint failed or errored
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitReporter is a struct of type ReporterInterface that collects lint reports
+// and writes them as a single JUnit XML document when flushed
+type JUnitReporter struct {
+	Out    io.Writer         // where to write the JUnit XML document
+	suites []*JUnitTestSuite // test suites of all lint reports so far
+}
+
+// Report adds a test suite for the target of the report with a test case for every lint
+func (r *JUnitReporter) Report(report *linter.LintReport) error {
+	suite := &JUnitTestSuite{
+		Name:      report.Target,
+		Tests:     len(report.Results),
+		Time:      fmt.Sprintf("%.3f", report.Duration.Seconds()),
+		Timestamp: report.StartTime.Format(time.RFC3339),
+	}
+
+	for _, result := range report.Results {
+		testCase := &JUnitTestCase{
+			Name:      result.Lint.ID,
+			ClassName: report.Target,
+		}
+		if result.SerialNumber != nil {
+			// test cases need unique names, so results for other SingleResponses say which one they are for
+			testCase.Name = fmt.Sprintf("%s (SingleResponse %d, serial number %s)", result.Lint.ID, result.SingleResponse, result.SerialNumber)
+		}
+
+		failure := &JUnitFailure{
+			Message: result.Info,
+			Type:    string(result.Status),
+			Text:    fmt.Sprintf("%s: %s (source: %s)", result.Lint.Info, result.Info, result.Lint.Source),
+		}
+
+		switch result.Status {
+		case linter.NotApplicable, linter.NotEffective:
+			testCase.Skipped = &JUnitSkipped{Message: result.Info}
+			suite.Skipped++
+		case linter.Notice, linter.Warn:
+			// JUnit has no notion of warnings, so these pass with the message as output
+			testCase.SystemOut = fmt.Sprintf("%s: %s", result.Status, result.Info)
+		case linter.Failed:
+			testCase.Failure = failure
+			suite.Failures++
+		case linter.Error:
+			testCase.Error = failure
+			suite.Errors++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	r.suites = append(r.suites, suite)
+
+	return nil
+}
+
+// Flush writes the JUnit XML document of all the lint reports so far
+func (r *JUnitReporter) Flush() error {
+	suites := &JUnitTestSuites{
+		Name:   ToolName,
+		Suites: r.suites,
+	}
+
+	for _, suite := range r.suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+	}
+
+	_, err := io.WriteString(r.Out, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(r.Out)
+	enc.Indent("", "  ")
+	err = enc.Encode(suites)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(r.Out, "\n")
+	return err
+}
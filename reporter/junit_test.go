@@ -0,0 +1,104 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"math/big"
+	"testing"
+)
+
+// TestJUnitReporter tests JUnitReporter, which collects lint reports and
+// writes them as a single JUnit XML document when flushed
+func TestJUnitReporter(t *testing.T) {
+	report := sampleReport()
+	report.Results = append(report.Results, &linter.LintResult{
+		Lint:   linter.Lints[2],
+		Status: linter.Error,
+		Info:   "error info",
+	}, &linter.LintResult{
+		Lint:   linter.Lints[3],
+		Status: linter.NotApplicable,
+		Info:   "not applicable info",
+	}, &linter.LintResult{
+		Lint:           linter.Lints[0],
+		Status:         linter.Passed,
+		Info:           "other single response info",
+		SingleResponse: 1,
+		SerialNumber:   big.NewInt(2),
+	})
+
+	var out bytes.Buffer
+	r := &JUnitReporter{Out: &out}
+
+	for i := 0; i < 2; i++ {
+		err := r.Report(report)
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+	}
+
+	if out.Len() != 0 {
+		t.Fatalf("Nothing should be written before flushing, instead got: %s", out.String())
+	}
+
+	err := r.Flush()
+	if err != nil {
+		t.Fatalf("Got error flushing JUnit XML document: %s", err.Error())
+	}
+
+	var suites JUnitTestSuites
+	err = xml.Unmarshal(out.Bytes(), &suites)
+	if err != nil {
+		t.Fatalf("Could not unmarshal JUnit XML document: %s", err.Error())
+	}
+
+	t.Run("Totals", func(t *testing.T) {
+		if suites.Tests != 10 || suites.Failures != 2 || suites.Errors != 2 || suites.Skipped != 2 {
+			t.Errorf("JUnit XML document has wrong totals, got: %s", out.String())
+		}
+	})
+
+	t.Run("Every target is a test suite", func(t *testing.T) {
+		if len(suites.Suites) != 2 {
+			t.Fatalf("JUnit XML document should have 2 test suites, instead has %d", len(suites.Suites))
+		}
+
+		suite := suites.Suites[0]
+		if suite.Name != report.Target || len(suite.Cases) != len(report.Results) {
+			t.Errorf("Test suite %s does not describe target %s", suite.Name, report.Target)
+		}
+
+		if suite.Cases[0].Name != report.Results[0].Lint.ID {
+			t.Errorf("Test case %s should be named after lint %s", suite.Cases[0].Name, report.Results[0].Lint.ID)
+		}
+
+		names := make(map[string]bool)
+		for _, testCase := range suite.Cases {
+			if names[testCase.Name] {
+				t.Errorf("Test case name %s is not unique", testCase.Name)
+			}
+			names[testCase.Name] = true
+		}
+	})
+
+	t.Run("Lint statuses are mapped to test case outcomes", func(t *testing.T) {
+		cases := suites.Suites[0].Cases
+
+		if cases[0].Failure != nil || cases[0].Error != nil {
+			t.Errorf("Passed lint should have no failure or error")
+		}
+
+		if cases[1].Failure == nil || cases[1].Failure.Message != "failed info" {
+			t.Errorf("Failed lint should have a failure")
+		}
+
+		if cases[2].Error == nil || cases[2].Error.Message != "error info" {
+			t.Errorf("Errored lint should have an error")
+		}
+
+		if cases[3].Skipped == nil || cases[3].Failure != nil || cases[3].Error != nil {
+			t.Errorf("Not applicable lint should be skipped")
+		}
+	})
+}
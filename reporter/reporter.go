@@ -0,0 +1,79 @@
+// Package reporter provides ways of outputting the lint reports produced by the linter
+package reporter
+
+import (
+	"fmt"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"io"
+	"sort"
+)
+
+const (
+	FormatText  = "text"  // human readable output
+	FormatJSON  = "json"  // one JSON document per linted OCSP response
+	FormatSARIF = "sarif" // a single SARIF log of all lint results
+	FormatJUnit = "junit" // a single JUnit XML document of all lint results
+)
+
+// ReporterInterface is an interface for outputting lint reports
+type ReporterInterface interface {
+	Report(*linter.LintReport) error // output or collect a single lint report
+	Flush() error                    // output anything collected, called after the last report
+}
+
+// NewReporter returns the reporter for the given output format
+func NewReporter(format string, out io.Writer, verbose bool) (ReporterInterface, error) {
+	switch format {
+	case FormatText:
+		return TextReporter{Out: out, Verbose: verbose}, nil
+	case FormatJSON:
+		return JSONReporter{Out: out}, nil
+	case FormatSARIF:
+		return &SARIFReporter{Out: out}, nil
+	case FormatJUnit:
+		return &JUnitReporter{Out: out}, nil
+	default:
+		return nil, fmt.Errorf("Unknown output format %s", format)
+	}
+}
+
+// TextReporter is a struct of type ReporterInterface that prints human readable lint reports
+type TextReporter struct {
+	Out     io.Writer // where to print the reports
+	Verbose bool      // whether to print all lints or only the ones that did not pass
+}
+
+// Report prints the status of the OCSP response and the results of all the lints run
+func (r TextReporter) Report(report *linter.LintReport) error {
+	fmt.Fprintf(r.Out, "OCSP Response status: %s \n\n", report.Status())
+	fmt.Fprintln(r.Out, "Printing lint results: ")
+
+	// sort a copy by status so printing prints the most severe lint results first
+	results := make([]*linter.LintResult, len(report.Results))
+	copy(results, report.Results)
+	sort.SliceStable(results, func(i, j int) bool {
+		return linter.StatusSeverity[results[i].Status] > linter.StatusSeverity[results[j].Status]
+	})
+
+	allPassed := true
+
+	for _, result := range results {
+		if !result.Status.Passing() {
+			allPassed = false
+		}
+		if !result.Status.Passing() || r.Verbose {
+			fmt.Fprintf(r.Out, "%s (%s): %s: %s \n", result.Lint.Info, result.Lint.ID, result.Status, result.Info)
+		}
+	}
+
+	if allPassed {
+		fmt.Fprintln(r.Out, "OCSP Response passed all lints")
+	}
+
+	return nil
+}
+
+// Flush does nothing as TextReporter prints each report as it is given
+func (r TextReporter) Flush() error {
+	return nil
+}
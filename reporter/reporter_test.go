@@ -0,0 +1,119 @@
+package reporter
+
+import (
+	"bytes"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"golang.org/x/crypto/ocsp"
+	"strings"
+	"testing"
+)
+
+// sampleReport returns a lint report with one passed and one failed lint result
+func sampleReport() *linter.LintReport {
+	return &linter.LintReport{
+		Target:     "google.com:443",
+		TargetURI:  "https://google.com:443",
+		RespStatus: ocsp.Good,
+		Results: []*linter.LintResult{
+			{
+				Lint:   linter.Lints[0],
+				Status: linter.Passed,
+				Info:   "passed info",
+			},
+			{
+				Lint:   linter.Lints[1],
+				Status: linter.Failed,
+				Info:   "failed info",
+			},
+		},
+	}
+}
+
+// TestTextReporter tests TextReporter, which prints human readable lint reports
+func TestTextReporter(t *testing.T) {
+	t.Run("Only prints lints that did not pass", func(t *testing.T) {
+		var out bytes.Buffer
+		err := TextReporter{Out: &out}.Report(sampleReport())
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+
+		if !strings.Contains(out.String(), "OCSP Response status: good") {
+			t.Errorf("Output should contain the response status, instead got: %s", out.String())
+		}
+
+		if !strings.Contains(out.String(), "failed info") {
+			t.Errorf("Output should contain failed lint, instead got: %s", out.String())
+		}
+
+		if strings.Contains(out.String(), "passed info") {
+			t.Errorf("Output should not contain passed lint when not verbose, instead got: %s", out.String())
+		}
+	})
+
+	t.Run("Prints unsuccessful response status", func(t *testing.T) {
+		report := sampleReport()
+		report.ResponseStatus = ocsp.Unauthorized
+
+		var out bytes.Buffer
+		err := TextReporter{Out: &out}.Report(report)
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+
+		if !strings.Contains(out.String(), "OCSP Response status: unauthorized") {
+			t.Errorf("Output should contain the unsuccessful response status, instead got: %s", out.String())
+		}
+	})
+
+	t.Run("Verbose prints all lints", func(t *testing.T) {
+		var out bytes.Buffer
+		err := TextReporter{Out: &out, Verbose: true}.Report(sampleReport())
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+
+		if !strings.Contains(out.String(), "passed info") {
+			t.Errorf("Output should contain passed lint when verbose, instead got: %s", out.String())
+		}
+	})
+
+	t.Run("All lints passed", func(t *testing.T) {
+		report := sampleReport()
+		report.Results = report.Results[:1]
+		report.Results = append(report.Results, &linter.LintResult{
+			Lint:   linter.Lints[3],
+			Status: linter.NotApplicable,
+			Info:   "not applicable info",
+		})
+
+		var out bytes.Buffer
+		err := TextReporter{Out: &out}.Report(report)
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+
+		if !strings.Contains(out.String(), "OCSP Response passed all lints") {
+			t.Errorf("Output should say all lints passed, instead got: %s", out.String())
+		}
+	})
+}
+
+// TestNewReporter tests NewReporter, which returns the reporter for an output format
+func TestNewReporter(t *testing.T) {
+	t.Run("Known formats", func(t *testing.T) {
+		for _, format := range []string{FormatText, FormatJSON, FormatSARIF, FormatJUnit} {
+			_, err := NewReporter(format, &bytes.Buffer{}, false)
+			if err != nil {
+				t.Errorf("Got error creating reporter for format %s: %s", format, err.Error())
+			}
+		}
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		_, err := NewReporter("blah", &bytes.Buffer{}, false)
+		if err == nil {
+			t.Errorf("Should have gotten error creating reporter for unknown format")
+		}
+	})
+}
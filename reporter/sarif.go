@@ -0,0 +1,173 @@
+package reporter
+
+import (
+	"encoding/json"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"io"
+)
+
+const (
+	SARIFVersion = "2.1.0"                                                 // version of SARIF that is output
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"         // schema of SARIF that is output
+	ToolName     = "ocsp-response-linter"                                  // name of this tool
+	ToolURI      = "https://github.com/googleinterns/ocsp-response-linter" // where to find out about this tool
+)
+
+// SARIFLevelMap maps lint statuses to SARIF result levels
+var SARIFLevelMap = map[linter.LintStatus]string{
+	linter.NotApplicable: "none",
+	linter.NotEffective:  "none",
+	linter.Passed:        "none",
+	linter.Notice:        "note",
+	linter.Warn:          "warning",
+	linter.Failed:        "error",
+	linter.Error:         "error",
+}
+
+// sarifLevel returns the SARIF result level of a lint status, "error" if the status is unknown
+// as every result needs a valid level
+func sarifLevel(status linter.LintStatus) string {
+	if level, ok := SARIFLevelMap[status]; ok {
+		return level
+	}
+
+	return "error"
+}
+
+// The following structs are the subset of the SARIF 2.1.0 format that is output,
+// see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+// SARIFLog is the top level SARIF object
+type SARIFLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*SARIFRun `json:"runs"`
+}
+
+// SARIFRun describes a single run of the linter
+type SARIFRun struct {
+	Tool    SARIFTool      `json:"tool"`
+	Results []*SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the linter and the lints it runs
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the linter and the lints it runs
+type SARIFDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a lint
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+	Help             SARIFMessage `json:"help"`
+}
+
+// SARIFMessage is a plain text SARIF message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult describes a lint that did not pass for a target
+type SARIFResult struct {
+	RuleID    string           `json:"ruleId"`
+	RuleIndex int              `json:"ruleIndex"`
+	Level     string           `json:"level"`
+	Message   SARIFMessage     `json:"message"`
+	Locations []*SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation describes the target a result was found in
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation describes the target a result was found in
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+}
+
+// SARIFArtifactLocation describes the target a result was found in
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFReporter is a struct of type ReporterInterface that collects lint reports
+// and writes them as a single SARIF log when flushed
+type SARIFReporter struct {
+	Out     io.Writer      // where to write the SARIF log
+	results []*SARIFResult // results of all lint reports so far
+}
+
+// Report adds a result to the SARIF log for every lint in the report that did not pass
+func (r *SARIFReporter) Report(report *linter.LintReport) error {
+	ruleIndices := make(map[*linter.LintStruct]int)
+	for idx, lint := range linter.Lints {
+		ruleIndices[lint] = idx
+	}
+
+	for _, result := range report.Results {
+		if result.Status.Passing() {
+			continue
+		}
+
+		r.results = append(r.results, &SARIFResult{
+			RuleID:    result.Lint.ID,
+			RuleIndex: ruleIndices[result.Lint],
+			Level:     sarifLevel(result.Status),
+			Message:   SARIFMessage{Text: result.Info},
+			Locations: []*SARIFLocation{
+				{
+					PhysicalLocation: SARIFPhysicalLocation{
+						ArtifactLocation: SARIFArtifactLocation{URI: report.TargetURI},
+					},
+				},
+			},
+		})
+	}
+
+	return nil
+}
+
+// Flush writes the SARIF log of all the lint reports so far
+func (r *SARIFReporter) Flush() error {
+	driver := SARIFDriver{
+		Name:           ToolName,
+		InformationURI: ToolURI,
+	}
+
+	for _, lint := range linter.Lints {
+		driver.Rules = append(driver.Rules, &SARIFRule{
+			ID:               lint.ID,
+			ShortDescription: SARIFMessage{Text: lint.Info},
+			Help:             SARIFMessage{Text: lint.Source},
+		})
+	}
+
+	results := r.results
+	if results == nil {
+		results = []*SARIFResult{} // SARIF requires results to be an array when present
+	}
+
+	log := &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []*SARIFRun{
+			{
+				Tool:    SARIFTool{Driver: driver},
+				Results: results,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(r.Out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/googleinterns/ocsp-response-linter/linter"
+	"testing"
+)
+
+// TestSARIFLevel tests sarifLevel, which returns the SARIF result level of a lint status
+func TestSARIFLevel(t *testing.T) {
+	if level := sarifLevel(linter.Warn); level != "warning" {
+		t.Errorf("Warning should have level warning, instead has %s", level)
+	}
+
+	if level := sarifLevel(linter.LintStatus("PASS")); level != "error" {
+		t.Errorf("Unknown status should have level error, instead has %s", level)
+	}
+}
+
+// TestSARIFReporter tests SARIFReporter, which collects lint reports and
+// writes them as a single SARIF log when flushed
+func TestSARIFReporter(t *testing.T) {
+	var out bytes.Buffer
+	r := &SARIFReporter{Out: &out}
+
+	report := sampleReport()
+	report.Results = append(report.Results, &linter.LintResult{
+		Lint:   linter.Lints[2],
+		Status: linter.Warn,
+		Info:   "warn info",
+	}, &linter.LintResult{
+		Lint:   linter.Lints[3],
+		Status: linter.Error,
+		Info:   "error info",
+	}, &linter.LintResult{
+		Lint:   linter.Lints[4],
+		Status: linter.NotApplicable,
+		Info:   "not applicable info",
+	})
+
+	for i := 0; i < 2; i++ {
+		err := r.Report(report)
+		if err != nil {
+			t.Fatalf("Got error reporting lint results: %s", err.Error())
+		}
+	}
+
+	if out.Len() != 0 {
+		t.Fatalf("Nothing should be written before flushing, instead got: %s", out.String())
+	}
+
+	err := r.Flush()
+	if err != nil {
+		t.Fatalf("Got error flushing SARIF log: %s", err.Error())
+	}
+
+	var log SARIFLog
+	err = json.Unmarshal(out.Bytes(), &log)
+	if err != nil {
+		t.Fatalf("Could not unmarshal SARIF log: %s", err.Error())
+	}
+
+	if log.Version != SARIFVersion || len(log.Runs) != 1 {
+		t.Fatalf("SARIF log should have version %s and one run, instead got: %s", SARIFVersion, out.String())
+	}
+
+	run := log.Runs[0]
+
+	t.Run("Every lint is a rule", func(t *testing.T) {
+		if len(run.Tool.Driver.Rules) != len(linter.Lints) {
+			t.Fatalf("SARIF log should have %d rules, instead has %d", len(linter.Lints), len(run.Tool.Driver.Rules))
+		}
+
+		rule := run.Tool.Driver.Rules[1]
+		if rule.ID != linter.Lints[1].ID || rule.ShortDescription.Text != linter.Lints[1].Info || rule.Help.Text != linter.Lints[1].Source {
+			t.Errorf("SARIF rule %s does not describe lint %s", rule.ID, linter.Lints[1].Info)
+		}
+	})
+
+	t.Run("Only lints that did not pass are results", func(t *testing.T) {
+		if len(run.Results) != 6 {
+			t.Fatalf("SARIF log should have 6 results, instead has %d", len(run.Results))
+		}
+
+		result := run.Results[0]
+		if result.RuleID != run.Tool.Driver.Rules[result.RuleIndex].ID {
+			t.Errorf("SARIF result rule id %s does not match its rule index %d", result.RuleID, result.RuleIndex)
+		}
+
+		if result.Level != "error" || result.Message.Text != "failed info" {
+			t.Errorf("SARIF result has wrong level %s: %s", result.Level, result.Message.Text)
+		}
+
+		if result.Locations[0].PhysicalLocation.ArtifactLocation.URI != report.TargetURI {
+			t.Errorf("SARIF result does not have the target as its location")
+		}
+
+		if run.Results[1].Level != "warning" {
+			t.Errorf("SARIF result for warning should have level warning, instead has %s", run.Results[1].Level)
+		}
+
+		if run.Results[2].Level != "error" {
+			t.Errorf("SARIF result for error should have level error, instead has %s", run.Results[2].Level)
+		}
+	})
+}